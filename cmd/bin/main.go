@@ -17,6 +17,9 @@ func main() {
 
 	serverCmd := flag.NewFlagSet("server", flag.ExitOnError)
 	seedCmd := flag.NewFlagSet("seed", flag.ExitOnError)
+	purgeCmd := flag.NewFlagSet("purge", flag.ExitOnError)
+	migrateCmd := flag.NewFlagSet("migrate", flag.ExitOnError)
+	configCmd := flag.NewFlagSet("config", flag.ExitOnError)
 	// wsCmd := flag.NewFlagSet("ws", flag.ExitOnError)
 
 	if len(os.Args) < 2 {
@@ -28,6 +31,12 @@ func main() {
 	switch os.Args[1] {
 	case "seed":
 		cmd.RunSeed(seedCmd, os.Args[2:])
+	case "purge":
+		cmd.RunPurge(purgeCmd, os.Args[2:])
+	case "migrate":
+		cmd.RunMigrate(migrateCmd, os.Args[2:])
+	case "config":
+		cmd.RunConfig(configCmd, os.Args[2:])
 	case "server":
 		cmd.RunServer(serverCmd, os.Args[2:])
 	default:
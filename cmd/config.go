@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"codebase-app/internal/infrastructure/config"
+	pkgConfig "codebase-app/pkg/config"
+	"flag"
+	"os"
+
+	"github.com/rs/zerolog/log"
+)
+
+// RunConfig inspects the loaded configuration, which was already
+// validated when it was read:
+//
+//	config print [--redact]
+func RunConfig(cmd *flag.FlagSet, args []string) {
+	var (
+		redact = cmd.Bool("redact", false, "hide the values of secrets")
+	)
+
+	if len(args) == 0 || args[0] != "print" {
+		log.Fatal().Msg("Missing config command: print")
+	}
+
+	if err := cmd.Parse(args[1:]); err != nil {
+		log.Fatal().Err(err).Msg("Error while parsing flags")
+	}
+
+	if err := pkgConfig.Print(os.Stdout, config.Envs, *redact); err != nil {
+		log.Fatal().Err(err).Msg("Error while printing configuration")
+	}
+}
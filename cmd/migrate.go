@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"codebase-app/db/migrations"
+	"codebase-app/internal/adapter"
+	"codebase-app/pkg/migrator"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// RunMigrate runs the embedded migrations:
+//
+//	migrate up [-steps n]
+//	migrate down [-steps n]
+//	migrate status
+//	migrate force <version>
+func RunMigrate(cmd *flag.FlagSet, args []string) {
+	var (
+		steps = cmd.Int("steps", 0, "number of migrations to apply or revert, all pending ones for up and 1 for down by default")
+	)
+
+	if len(args) == 0 {
+		log.Fatal().Msg("Missing migrate command: up, down, status or force")
+	}
+
+	action := args[0]
+	if err := cmd.Parse(args[1:]); err != nil {
+		log.Fatal().Err(err).Msg("Error while parsing flags")
+	}
+
+	adapter.Adapters.Sync(
+		adapter.WithShopeefunPostgres(),
+	)
+	defer func() {
+		if err := adapter.Adapters.Unsync(); err != nil {
+			log.Fatal().Err(err).Msg("Error while closing database connection")
+		}
+	}()
+
+	m, err := migrator.New(adapter.Adapters.ShopeefunPostgres, migrations.FS)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error while reading migrations")
+	}
+
+	ctx := context.Background()
+
+	switch action {
+	case "up":
+		count, err := m.Up(ctx, *steps)
+		if err != nil {
+			log.Fatal().Err(err).Int("applied", count).Msg("Error while applying migrations")
+		}
+		log.Info().Int("applied", count).Msg("Migrations are up to date")
+	case "down":
+		if *steps == 0 {
+			*steps = 1
+		}
+		count, err := m.Down(ctx, *steps)
+		if err != nil {
+			log.Fatal().Err(err).Int("reverted", count).Msg("Error while reverting migrations")
+		}
+		log.Info().Int("reverted", count).Msg("Migrations reverted")
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Error while reading migration status")
+		}
+		printStatus(statuses)
+	case "force":
+		if cmd.NArg() != 1 {
+			log.Fatal().Msg("Usage: migrate force <version>")
+		}
+		version, err := strconv.ParseInt(cmd.Arg(0), 10, 64)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Invalid version")
+		}
+		if err := m.Force(ctx, version); err != nil {
+			log.Fatal().Err(err).Msg("Error while forcing migration version")
+		}
+		log.Info().Int64("version", version).Msg("Migration version forced")
+	default:
+		log.Fatal().Str("command", action).Msg("Unknown migrate command, use up, down, status or force")
+	}
+}
+
+// AutoMigrate applies pending migrations on server start. Replicas started
+// together wait on the migrator's advisory lock, so only one of them runs them.
+func AutoMigrate(ctx context.Context) {
+	m, err := migrator.New(adapter.Adapters.ShopeefunPostgres, migrations.FS)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error while reading migrations")
+	}
+
+	count, err := m.Up(ctx, 0)
+	if err != nil {
+		log.Fatal().Err(err).Int("applied", count).Msg("Error while applying migrations")
+	}
+
+	log.Info().Int("applied", count).Msg("Migrations are up to date")
+}
+
+func printStatus(statuses []migrator.Status) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+
+	for _, s := range statuses {
+		var (
+			state     = "pending"
+			appliedAt = "-"
+		)
+
+		if s.AppliedAt != nil {
+			state = "applied"
+			appliedAt = s.AppliedAt.Format(time.RFC3339)
+		}
+		if s.Modified {
+			state = "modified"
+		}
+		if s.Missing {
+			state = "missing"
+		}
+
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+	}
+
+	_ = w.Flush()
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"codebase-app/internal/adapter"
+	"codebase-app/internal/infrastructure/config"
+	integration "codebase-app/internal/integration/localstorage"
+	categoryEntity "codebase-app/internal/module/product-categories/entity"
+	categoryRepository "codebase-app/internal/module/product-categories/repository"
+	categoryService "codebase-app/internal/module/product-categories/service"
+	productEntity "codebase-app/internal/module/products/entity"
+	productRepository "codebase-app/internal/module/products/repository"
+	productService "codebase-app/internal/module/products/service"
+	shopEntity "codebase-app/internal/module/shop/entity"
+	shopRepository "codebase-app/internal/module/shop/repository"
+	shopService "codebase-app/internal/module/shop/service"
+	"codebase-app/pkg/database"
+	"context"
+	"flag"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// RunPurge hard-deletes products, shops and categories that were
+// soft-deleted longer than the retention period ago. Products go first so
+// their shops and categories are no longer referenced, all in one
+// transaction so a failed run leaves nothing half purged.
+func RunPurge(cmd *flag.FlagSet, args []string) {
+	var (
+		retention = cmd.Int("retention", config.Envs.Purge.RetentionDays, "days a soft-deleted row is kept")
+	)
+
+	if err := cmd.Parse(args); err != nil {
+		log.Fatal().Err(err).Msg("Error while parsing flags")
+	}
+
+	if *retention < 0 {
+		log.Fatal().Int("retention", *retention).Msg("Retention must not be negative")
+	}
+
+	adapter.Adapters.Sync(
+		adapter.WithShopeefunPostgres(),
+	)
+	defer func() {
+		if err := adapter.Adapters.Unsync(); err != nil {
+			log.Fatal().Err(err).Msg("Error while closing database connection")
+		}
+	}()
+
+	var (
+		ctx    = context.Background()
+		db     = adapter.Adapters.ShopeefunPostgres
+		before = time.Now().AddDate(0, 0, -*retention)
+
+		products   = productService.NewProductService(productRepository.NewProductRepository(db))
+		shops      = shopService.NewShopService(shopRepository.NewShopRepository(db), integration.NewLocalStorageIntegration())
+		categories = categoryService.NewProductCategoriesService(categoryRepository.NewProductCategoriesRepository(db))
+	)
+
+	log.Info().Time("before", before).Msg("Purging soft-deleted rows")
+
+	var purgedProducts, purgedShops, purgedCategories int64
+
+	err := database.NewTxManager(db).WithTx(ctx, func(ctx context.Context) (err error) {
+		purgedProducts, err = products.PurgeProducts(ctx, &productEntity.PurgeRequest{Before: before})
+		if err != nil {
+			log.Error().Err(err).Msg("Error while purging products")
+			return err
+		}
+
+		purgedShops, err = shops.PurgeShops(ctx, &shopEntity.PurgeRequest{Before: before})
+		if err != nil {
+			log.Error().Err(err).Msg("Error while purging shops")
+			return err
+		}
+
+		purgedCategories, err = categories.PurgeProductCategories(ctx, &categoryEntity.PurgeRequest{Before: before})
+		if err != nil {
+			log.Error().Err(err).Msg("Error while purging categories")
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error while purging soft-deleted rows")
+	}
+
+	log.Info().
+		Int64("products", purgedProducts).
+		Int64("shops", purgedShops).
+		Int64("categories", purgedCategories).
+		Msg("Purge finished")
+}
@@ -12,6 +12,8 @@ func RunSeed(cmd *flag.FlagSet, args []string) {
 	var (
 		table = cmd.String("table", "", "seed to run")
 		total = cmd.Int("total", 1, "total of records to seed")
+		seed  = cmd.Uint64("seed", 0, "random seed, the same seed generates the same data (0 = random)")
+		batch = cmd.Int("batch", 10000, "rows copied per transaction")
 	)
 
 	if err := cmd.Parse(args); err != nil {
@@ -27,5 +29,5 @@ func RunSeed(cmd *flag.FlagSet, args []string) {
 		}
 	}()
 
-	seeds.Execute(adapter.Adapters.ShopeefunPostgres, *table, *total)
+	seeds.Execute(adapter.Adapters.ShopeefunPostgres, *table, *total, *seed, *batch)
 }
@@ -4,19 +4,23 @@ import (
 	"codebase-app/internal/adapter"
 	"codebase-app/internal/infrastructure"
 	"codebase-app/internal/infrastructure/config"
+	"codebase-app/internal/infrastructure/lifecycle"
+	"codebase-app/internal/infrastructure/metrics"
+	"codebase-app/internal/infrastructure/tracing"
+	"codebase-app/internal/middleware"
+	idempotencyWorker "codebase-app/internal/module/idempotency/worker"
+	productWorker "codebase-app/internal/module/products/worker"
+	rateLimitWorker "codebase-app/internal/module/ratelimit/worker"
 	"codebase-app/internal/route"
 	"codebase-app/pkg/validator"
+	"context"
 	"flag"
-	"os"
-	"os/signal"
-	"runtime"
-	"syscall"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/limiter"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
 	"github.com/gofiber/fiber/v2/middleware/monitor"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
@@ -25,9 +29,14 @@ func RunServer(cmd *flag.FlagSet, args []string) {
 	var (
 		envs        = config.Envs
 		flagAppPort = cmd.String("port", "4000", "Application port")
+		autoMigrate = cmd.Bool("auto-migrate", false, "Apply pending migrations before serving")
 		SERVER_PORT string
 	)
 
+	if err := envs.ValidateServer(); err != nil {
+		log.Fatal().Err(err).Msg("Invalid server configuration")
+	}
+
 	logLevel, err := zerolog.ParseLevel(envs.App.LogLevel)
 	if err != nil {
 		logLevel = zerolog.InfoLevel
@@ -43,33 +52,81 @@ func RunServer(cmd *flag.FlagSet, args []string) {
 		SERVER_PORT = *flagAppPort
 	}
 
-	app := fiber.New()
+	app := fiber.New(fiber.Config{
+		BodyLimit: int(envs.App.BodyLimit),
+		// behind a load balancer c.IP() is the client from ProxyHeader, but only
+		// on requests that come from one of the trusted proxies
+		ProxyHeader:             envs.App.ProxyHeader,
+		EnableTrustedProxyCheck: envs.App.ProxyHeader != "",
+		TrustedProxies:          envs.App.TrustedProxies,
+		EnableIPValidation:      true,
+	})
 
 	// Application Middlewares
-	if envs.App.Environtment == "production" {
-		app.Use(limiter.New(limiter.Config{
-			Max:        50,
-			Expiration: 30 * time.Second,
-		}))
-	}
-
-	app.Use(cors.New(cors.Config{
-		AllowOrigins: "*",
-		AllowMethods: "GET,POST,PUT,DELETE,PATCH,OPTIONS,HEAD",
-		AllowHeaders: "Origin,Content-Type,Accept,Content-Length,Accept-Language,Accept-Encoding,Connection,Access-Control-Allow-Origin,Authorization",
-	}))
+	// CORS is rebuilt when the configuration is reloaded, rate limits are
+	// set per route group, see route.SetupRoutes
+	app.Use(middleware.SecurityHeaders(envs))
+	app.Use(middleware.NewReloadable(middleware.Cors).Handle)
+	app.Use(middleware.Tracing)
+	app.Use(middleware.RequestLog)
+	app.Use(middleware.Metrics)
 	// End Application Middlewares
 
 	adapter.Adapters.Sync(
 		adapter.WithRestServer(app),
 		adapter.WithShopeefunPostgres(),
 		adapter.WithValidator(validator.NewValidator()),
+		adapter.WithRateLimitStore(),
 	)
 
-	infrastructure.InitializeLogger(envs.App.Environtment, envs.App.LogFile, logLevel)
-	app.Get("/metrics", monitor.New(monitor.Config{Title: config.Envs.App.Name + config.Envs.App.Environtment + " Metrics"}))
+	logFile := infrastructure.InitializeLogger(envs.App.Environtment, envs.App.LogFile, logLevel)
+
+	// hooks run in reverse order: the log file is closed last
+	lc := lifecycle.New(time.Duration(envs.Shutdown.Timeout))
+	lc.OnShutdown("logger", func(ctx context.Context) error {
+		log.Info().Msg("Server gracefully stopped")
+		return logFile.Close()
+	})
+
+	shutdownTracing, err := tracing.Init(context.Background(), tracing.Config{
+		ServiceName: envs.App.Name,
+		Environment: envs.App.Environtment,
+		Exporter:    envs.Tracing.Exporter,
+		File:        envs.Tracing.File,
+		SampleRatio: envs.Tracing.SampleRatio,
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error while initializing tracing")
+	}
+	lc.OnShutdown("tracing", shutdownTracing)
+	// drains the rest server, then closes the database
+	lc.OnShutdown("adapters", func(ctx context.Context) error {
+		return adapter.Adapters.Unsync()
+	})
+
+	if *autoMigrate {
+		AutoMigrate(context.Background())
+	}
+
+	metrics.RegisterDB("shopeefun_postgres", adapter.Adapters.ShopeefunPostgres)
+	app.Get("/metrics", adaptor.HTTPHandler(promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{})))
+	app.Get("/admin/monitor", middleware.AuthBearer, middleware.AuthRole([]string{"admin"}), monitor.New(monitor.Config{Title: config.Envs.App.Name + config.Envs.App.Environtment + " Metrics"}))
 	route.SetupRoutes(app)
 
+	config.OnReload(func(cfg *config.Config) {
+		level, err := zerolog.ParseLevel(cfg.App.LogLevel)
+		if err != nil {
+			return
+		}
+		zerolog.SetGlobalLevel(level)
+	})
+	lc.Go("config-watcher", config.Watch)
+	lc.Go("product-scheduler", productWorker.NewScheduler(time.Duration(envs.Worker.ProductScheduleInterval)).Start)
+	lc.Go("idempotency-cleaner", idempotencyWorker.NewCleaner(time.Duration(envs.Worker.IdempotencyCleanupInterval)).Start)
+	if store := adapter.Adapters.RateLimitStore; store != nil {
+		lc.Go("rate-limit-cleaner", rateLimitWorker.NewCleaner(store, time.Duration(envs.Worker.RateLimitCleanupInterval)).Start)
+	}
+
 	// print all routes that are registered
 	// for _, route := range app.Stack() {
 	// 	for _, handler := range route {
@@ -86,22 +143,19 @@ func RunServer(cmd *flag.FlagSet, args []string) {
 	}()
 	// End Run server in goroutine
 
-	// Handle graceful shutdown
-	quit := make(chan os.Signal, 1)
-
-	shutdownSignals := []os.Signal{os.Interrupt, syscall.SIGTERM, syscall.SIGINT}
-	if runtime.GOOS == "windows" {
-		shutdownSignals = []os.Signal{os.Interrupt}
-	}
+	// runs first on shutdown: fail readiness so load balancers stop routing
+	// new requests here before the listener closes
+	lc.OnShutdown("readiness", func(ctx context.Context) error {
+		adapter.Adapters.SetDraining()
 
-	signal.Notify(quit, shutdownSignals...)
-	<-quit
-	log.Info().Msg("Server is shutting down ...")
+		select {
+		case <-time.After(time.Duration(envs.Shutdown.ReadinessDelay)):
+		case <-ctx.Done():
+		}
+		return nil
+	})
 
-	err = adapter.Adapters.Unsync()
-	if err != nil {
-		log.Error().Msgf("Error while closing adapters: %v", err)
+	if err := lc.Wait(); err != nil {
+		log.Error().Err(err).Msg("Error while shutting down")
 	}
-
-	log.Info().Msg("Server gracefully stopped")
 }
@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS shops_slug_unique;
+
+ALTER TABLE shops
+    DROP COLUMN IF EXISTS slug,
+    DROP COLUMN IF EXISTS logo_url,
+    DROP COLUMN IF EXISTS banner_url,
+    DROP COLUMN IF EXISTS phone,
+    DROP COLUMN IF EXISTS email,
+    DROP COLUMN IF EXISTS address,
+    DROP COLUMN IF EXISTS city,
+    DROP COLUMN IF EXISTS province,
+    DROP COLUMN IF EXISTS postal_code,
+    DROP COLUMN IF EXISTS location;
@@ -0,0 +1,27 @@
+CREATE EXTENSION IF NOT EXISTS postgis;
+
+ALTER TABLE shops
+    ADD COLUMN IF NOT EXISTS slug VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS logo_url TEXT,
+    ADD COLUMN IF NOT EXISTS banner_url TEXT,
+    ADD COLUMN IF NOT EXISTS phone VARCHAR(32),
+    ADD COLUMN IF NOT EXISTS email VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS address TEXT,
+    ADD COLUMN IF NOT EXISTS city VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS province VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS postal_code VARCHAR(16),
+    ADD COLUMN IF NOT EXISTS location GEOGRAPHY(Point, 4326);
+
+-- backfill slugs for existing shops, suffixed with the id prefix to keep them unique;
+-- the name part is cut to 91 characters so slugs fit the 100 the validator allows,
+-- and names without any letter or digit fall back to "shop"
+UPDATE shops
+SET slug = COALESCE(
+        NULLIF(TRIM(BOTH '-' FROM LEFT(TRIM(BOTH '-' FROM LOWER(REGEXP_REPLACE(name, '[^a-zA-Z0-9]+', '-', 'g'))), 91)), ''),
+        'shop'
+    ) || '-' || SUBSTRING(id::TEXT, 1, 8)
+WHERE slug IS NULL;
+
+ALTER TABLE shops ALTER COLUMN slug SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS shops_slug_unique ON shops (slug);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS shops_location_gist;
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS shops_location_gist ON shops USING GIST (location);
@@ -0,0 +1,9 @@
+DROP FUNCTION IF EXISTS shop_is_open(UUID, TIMESTAMP WITH TIME ZONE);
+
+DROP TABLE IF EXISTS shop_opening_hours;
+
+ALTER TABLE shops
+    DROP COLUMN IF EXISTS timezone,
+    DROP COLUMN IF EXISTS vacation_start_at,
+    DROP COLUMN IF EXISTS vacation_end_at,
+    DROP COLUMN IF EXISTS vacation_message;
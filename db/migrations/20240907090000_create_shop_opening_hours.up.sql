@@ -0,0 +1,59 @@
+ALTER TABLE shops
+    ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'Asia/Jakarta',
+    ADD COLUMN IF NOT EXISTS vacation_start_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS vacation_end_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS vacation_message VARCHAR(255);
+
+CREATE TABLE IF NOT EXISTS shop_opening_hours (
+    shop_id UUID NOT NULL,
+    weekday SMALLINT NOT NULL CHECK (weekday BETWEEN 0 AND 6), -- 0 = sunday, the day the shop opens
+    open_time TIME NOT NULL,
+    close_time TIME NOT NULL CHECK (close_time <> open_time), -- before open_time when the shop closes after midnight
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (shop_id, weekday),
+    FOREIGN KEY (shop_id) REFERENCES shops(id) ON DELETE CASCADE
+);
+
+-- shop_is_open reports whether a shop accepts orders at the given time.
+-- A shop without opening hours is open all day, a shop on vacation is always closed.
+-- Hours that close before they open run past midnight into the next weekday.
+-- It returns NULL for an unknown shop.
+CREATE OR REPLACE FUNCTION shop_is_open(p_shop_id UUID, p_at TIMESTAMP WITH TIME ZONE DEFAULT now())
+RETURNS BOOLEAN AS $$
+    SELECT
+        NOT (
+            s.vacation_start_at IS NOT NULL
+            AND p_at >= s.vacation_start_at
+            AND (s.vacation_end_at IS NULL OR p_at < s.vacation_end_at)
+        )
+        AND (
+            NOT EXISTS (SELECT 1 FROM shop_opening_hours h WHERE h.shop_id = s.id)
+            OR EXISTS (
+                SELECT 1
+                FROM shop_opening_hours h
+                WHERE
+                    h.shop_id = s.id
+                    AND (
+                        (
+                            h.weekday = l.weekday
+                            AND l.at_time >= h.open_time
+                            AND (h.close_time < h.open_time OR l.at_time < h.close_time)
+                        )
+                        OR (
+                            -- the tail of the previous weekday's hours
+                            h.close_time < h.open_time
+                            AND h.weekday = (l.weekday + 6) % 7
+                            AND l.at_time < h.close_time
+                        )
+                    )
+            )
+        )
+    FROM shops s
+    CROSS JOIN LATERAL (
+        SELECT
+            EXTRACT(DOW FROM p_at AT TIME ZONE s.timezone)::SMALLINT AS weekday,
+            (p_at AT TIME ZONE s.timezone)::TIME AS at_time
+    ) l
+    WHERE s.id = p_shop_id
+$$ LANGUAGE SQL STABLE;
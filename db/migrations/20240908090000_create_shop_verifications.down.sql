@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS shop_verifications;
+
+ALTER TABLE shops
+    DROP COLUMN IF EXISTS verified_at;
@@ -0,0 +1,22 @@
+ALTER TABLE shops
+    ADD COLUMN IF NOT EXISTS verified_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE IF NOT EXISTS shop_verifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    shop_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    ktp_file TEXT NOT NULL,
+    npwp_file TEXT NOT NULL,
+    reject_reason TEXT,
+    reviewed_by UUID,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+
+    FOREIGN KEY (shop_id) REFERENCES shops(id)
+);
+
+-- a shop can only have one request waiting for review
+CREATE UNIQUE INDEX IF NOT EXISTS shop_verifications_pending_unique ON shop_verifications (shop_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS shop_verifications_status_idx ON shop_verifications (status, created_at);
@@ -0,0 +1,48 @@
+CREATE TABLE IF NOT EXISTS product_reviews (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    body TEXT,
+    images TEXT[] NOT NULL DEFAULT '{}',
+    seller_reply TEXT,
+    seller_replied_at TIMESTAMP WITH TIME ZONE,
+    is_hidden BOOLEAN NOT NULL DEFAULT FALSE,
+    flag_count INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+
+    UNIQUE (product_id, user_id),
+    FOREIGN KEY (product_id) REFERENCES products(id)
+);
+
+CREATE INDEX IF NOT EXISTS product_reviews_product_idx ON product_reviews (product_id, created_at DESC) WHERE is_hidden = FALSE;
+
+CREATE TABLE IF NOT EXISTS product_review_flags (
+    review_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    reason VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (review_id, user_id),
+    FOREIGN KEY (review_id) REFERENCES product_reviews(id) ON DELETE CASCADE
+);
+
+-- product_rating_summaries is maintained by the review repository in the same
+-- transaction as every review write, hidden reviews are not counted.
+CREATE TABLE IF NOT EXISTS product_rating_summaries (
+    product_id UUID PRIMARY KEY,
+    rating_count INT NOT NULL DEFAULT 0,
+    rating_sum INT NOT NULL DEFAULT 0,
+    rating_average NUMERIC(3, 2) NOT NULL DEFAULT 0,
+    star_1 INT NOT NULL DEFAULT 0,
+    star_2 INT NOT NULL DEFAULT 0,
+    star_3 INT NOT NULL DEFAULT 0,
+    star_4 INT NOT NULL DEFAULT 0,
+    star_5 INT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+
+    FOREIGN KEY (product_id) REFERENCES products(id)
+);
+
+CREATE INDEX IF NOT EXISTS product_rating_summaries_average_idx ON product_rating_summaries (rating_average DESC, rating_count DESC);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS product_inquiry_reports;
+DROP TABLE IF EXISTS product_inquiries;
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS product_inquiries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    question TEXT NOT NULL,
+    answer TEXT,
+    answered_by UUID,
+    answered_at TIMESTAMP WITH TIME ZONE,
+    report_count INT NOT NULL DEFAULT 0,
+    is_hidden BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+
+    FOREIGN KEY (product_id) REFERENCES products(id)
+);
+
+CREATE INDEX IF NOT EXISTS product_inquiries_product_idx ON product_inquiries (product_id, created_at DESC) WHERE is_hidden = FALSE;
+
+CREATE TABLE IF NOT EXISTS product_inquiry_reports (
+    inquiry_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    reason VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (inquiry_id, user_id),
+    FOREIGN KEY (inquiry_id) REFERENCES product_inquiries(id) ON DELETE CASCADE
+);
@@ -0,0 +1,14 @@
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'pending_review', 'active', 'archived')),
+    ADD COLUMN IF NOT EXISTS publish_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS unpublish_at TIMESTAMP WITH TIME ZONE,
+    DROP COLUMN IF EXISTS availability;
+
+-- products created before statuses existed were already public
+UPDATE products SET status = 'active';
+
+CREATE INDEX IF NOT EXISTS products_status_idx ON products (status) WHERE deleted_at IS NULL;
+
+-- used by the scheduler to find products whose publish window starts or ends
+CREATE INDEX IF NOT EXISTS products_publish_at_idx ON products (publish_at) WHERE status = 'draft' AND publish_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS products_unpublish_at_idx ON products (unpublish_at) WHERE status = 'active' AND unpublish_at IS NOT NULL;
@@ -0,0 +1,3 @@
+DROP FUNCTION IF EXISTS product_effective_price(UUID, DECIMAL, TIMESTAMP WITH TIME ZONE);
+DROP TABLE IF EXISTS product_sales;
+DROP TABLE IF EXISTS product_prices;
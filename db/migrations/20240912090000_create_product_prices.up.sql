@@ -0,0 +1,45 @@
+CREATE TABLE IF NOT EXISTS product_prices (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL,
+    price DECIMAL(19, 4) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+
+    FOREIGN KEY (product_id) REFERENCES products(id)
+);
+
+CREATE INDEX IF NOT EXISTS product_prices_product_idx ON product_prices (product_id, created_at DESC);
+
+-- start every existing product's history with its current price
+INSERT INTO product_prices (product_id, price, created_at)
+SELECT id, price, created_at FROM products;
+
+CREATE TABLE IF NOT EXISTS product_sales (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL,
+    sale_price DECIMAL(19, 4) NOT NULL CHECK (sale_price >= 0),
+    start_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    deleted_at TIMESTAMP WITH TIME ZONE,
+
+    CHECK (end_at > start_at),
+    FOREIGN KEY (product_id) REFERENCES products(id)
+);
+
+CREATE INDEX IF NOT EXISTS product_sales_product_idx ON product_sales (product_id, start_at, end_at) WHERE deleted_at IS NULL;
+
+-- product_effective_price returns the price a buyer pays at p_at: the lowest
+-- running sale price, or the regular price when no sale is running.
+CREATE OR REPLACE FUNCTION product_effective_price(p_product_id UUID, p_price DECIMAL, p_at TIMESTAMP WITH TIME ZONE DEFAULT now())
+RETURNS DECIMAL AS $$
+    SELECT LEAST(p_price, COALESCE((
+        SELECT MIN(sale_price)
+        FROM product_sales
+        WHERE
+            product_id = p_product_id
+            AND deleted_at IS NULL
+            AND start_at <= p_at
+            AND end_at > p_at
+    ), p_price));
+$$ LANGUAGE SQL STABLE;
@@ -0,0 +1,6 @@
+ALTER TABLE product_prices
+    DROP COLUMN IF EXISTS currency;
+ALTER TABLE products
+    DROP COLUMN IF EXISTS currency;
+
+DROP TABLE IF EXISTS currencies;
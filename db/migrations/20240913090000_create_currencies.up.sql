@@ -0,0 +1,19 @@
+-- rate is the value of one unit of the currency in the base currency (IDR)
+CREATE TABLE IF NOT EXISTS currencies (
+    code CHAR(3) PRIMARY KEY,
+    rate DECIMAL(24, 8) NOT NULL CHECK (rate > 0),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+
+    CONSTRAINT currencies_base_rate CHECK (code <> 'IDR' OR rate = 1)
+);
+
+INSERT INTO currencies (code, rate) VALUES ('IDR', 1) ON CONFLICT (code) DO NOTHING;
+
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'IDR' REFERENCES currencies(code);
+
+-- each price history row keeps the currency it was set in, every product was in IDR so far
+ALTER TABLE product_prices
+    ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'IDR' REFERENCES currencies(code);
+ALTER TABLE product_prices
+    ALTER COLUMN currency DROP DEFAULT;
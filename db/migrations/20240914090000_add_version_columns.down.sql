@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS product_categories_bump_version ON product_categories;
+DROP TRIGGER IF EXISTS shops_bump_version ON shops;
+DROP TRIGGER IF EXISTS products_bump_version ON products;
+DROP FUNCTION IF EXISTS bump_version();
+
+ALTER TABLE product_categories DROP COLUMN IF EXISTS version;
+ALTER TABLE shops DROP COLUMN IF EXISTS version;
+ALTER TABLE products DROP COLUMN IF EXISTS version;
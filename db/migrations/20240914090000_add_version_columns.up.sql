@@ -0,0 +1,25 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+ALTER TABLE shops ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+ALTER TABLE product_categories ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+
+-- bump_version increments the row version on every update, so writes that
+-- do not go through If-Match (workers, status changes) still change the ETag.
+CREATE OR REPLACE FUNCTION bump_version()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.version := OLD.version + 1;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER products_bump_version
+    BEFORE UPDATE ON products
+    FOR EACH ROW EXECUTE FUNCTION bump_version();
+
+CREATE OR REPLACE TRIGGER shops_bump_version
+    BEFORE UPDATE ON shops
+    FOR EACH ROW EXECUTE FUNCTION bump_version();
+
+CREATE OR REPLACE TRIGGER product_categories_bump_version
+    BEFORE UPDATE ON product_categories
+    FOR EACH ROW EXECUTE FUNCTION bump_version();
@@ -0,0 +1,3 @@
+-- the backfilled products cannot be told apart from the ones deleted with
+-- their shop later on, so there is nothing to undo
+SELECT 1;
@@ -0,0 +1,9 @@
+-- products of shops deleted before deletes cascaded were left visible,
+-- delete them with the shop's timestamp so restoring the shop restores them
+UPDATE products p
+SET deleted_at = s.deleted_at
+FROM shops s
+WHERE
+    s.id = p.shop_id
+    AND s.deleted_at IS NOT NULL
+    AND p.deleted_at IS NULL;
@@ -0,0 +1,10 @@
+-- rate_limits counts requests per client and route group in fixed windows,
+-- shared by all replicas. The counts are disposable, so the table is not
+-- written to the WAL and is emptied after a crash.
+CREATE UNLOGGED TABLE IF NOT EXISTS rate_limits (
+    key TEXT PRIMARY KEY,
+    hits INT NOT NULL,
+    reset_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS rate_limits_reset_at_idx ON rate_limits (reset_at);
@@ -0,0 +1,329 @@
+package seeds
+
+import (
+	"codebase-app/pkg"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// city is where a seeded shop is located, with its coordinates and timezone.
+type city struct {
+	name     string
+	province string
+	lat, lng float64
+	timezone string
+}
+
+var cities = []city{
+	{"Jakarta Selatan", "DKI Jakarta", -6.2615, 106.8106, "Asia/Jakarta"},
+	{"Jakarta Barat", "DKI Jakarta", -6.1674, 106.7637, "Asia/Jakarta"},
+	{"Jakarta Timur", "DKI Jakarta", -6.2250, 106.9004, "Asia/Jakarta"},
+	{"Bandung", "Jawa Barat", -6.9175, 107.6191, "Asia/Jakarta"},
+	{"Bekasi", "Jawa Barat", -6.2383, 106.9756, "Asia/Jakarta"},
+	{"Bogor", "Jawa Barat", -6.5971, 106.8060, "Asia/Jakarta"},
+	{"Depok", "Jawa Barat", -6.4025, 106.7942, "Asia/Jakarta"},
+	{"Tangerang", "Banten", -6.1783, 106.6319, "Asia/Jakarta"},
+	{"Semarang", "Jawa Tengah", -6.9667, 110.4167, "Asia/Jakarta"},
+	{"Solo", "Jawa Tengah", -7.5755, 110.8243, "Asia/Jakarta"},
+	{"Yogyakarta", "DI Yogyakarta", -7.7956, 110.3695, "Asia/Jakarta"},
+	{"Surabaya", "Jawa Timur", -7.2575, 112.7521, "Asia/Jakarta"},
+	{"Malang", "Jawa Timur", -7.9666, 112.6326, "Asia/Jakarta"},
+	{"Medan", "Sumatera Utara", 3.5952, 98.6722, "Asia/Jakarta"},
+	{"Palembang", "Sumatera Selatan", -2.9761, 104.7754, "Asia/Jakarta"},
+	{"Padang", "Sumatera Barat", -0.9471, 100.4172, "Asia/Jakarta"},
+	{"Pekanbaru", "Riau", 0.5071, 101.4478, "Asia/Jakarta"},
+	{"Pontianak", "Kalimantan Barat", -0.0263, 109.3425, "Asia/Jakarta"},
+	{"Denpasar", "Bali", -8.6705, 115.2126, "Asia/Makassar"},
+	{"Balikpapan", "Kalimantan Timur", -1.2379, 116.8529, "Asia/Makassar"},
+	{"Makassar", "Sulawesi Selatan", -5.1477, 119.4327, "Asia/Makassar"},
+	{"Manado", "Sulawesi Utara", 1.4748, 124.8421, "Asia/Makassar"},
+	{"Ambon", "Maluku", -3.6954, 128.1814, "Asia/Jayapura"},
+	{"Jayapura", "Papua", -2.5337, 140.7181, "Asia/Jayapura"},
+}
+
+// catalogue maps the seeded categories to the products and brands sold in them.
+var catalogue = []struct {
+	category string
+	products []string
+	brands   []string
+	minPrice int
+	maxPrice int
+}{
+	{"Elektronik", []string{"Smart TV 43 inch", "Speaker Bluetooth", "Rice Cooker", "Blender", "Kipas Angin", "Setrika Uap"}, []string{"Polytron", "Sharp", "Miyako", "Cosmos", "Philips"}, 75_000, 6_000_000},
+	{"Handphone & Tablet", []string{"Smartphone", "Tablet", "Charger Fast Charging", "Casing HP", "Powerbank 10000mAh", "Tempered Glass"}, []string{"Samsung", "Xiaomi", "Oppo", "Vivo", "Realme"}, 15_000, 12_000_000},
+	{"Komputer & Laptop", []string{"Laptop", "Mouse Wireless", "Keyboard Mekanik", "Monitor 24 inch", "Flashdisk 64GB", "SSD 512GB"}, []string{"Asus", "Lenovo", "Acer", "Logitech", "Sandisk"}, 40_000, 20_000_000},
+	{"Fashion Pria", []string{"Kemeja Batik", "Kaos Polos", "Celana Chino", "Jaket Bomber", "Sarung Tenun", "Peci Hitam"}, []string{"Eiger", "Erigo", "Batik Keris", "Wadimor", "Executive"}, 25_000, 750_000},
+	{"Fashion Muslim", []string{"Gamis", "Hijab Segi Empat", "Mukena Travel", "Koko Lengan Panjang", "Pashmina", "Tunik"}, []string{"Zoya", "Elzatta", "Rabbani", "Shafira", "Wardah"}, 30_000, 900_000},
+	{"Sepatu", []string{"Sneakers", "Sepatu Lari", "Sandal Jepit", "Sepatu Pantofel", "Sepatu Sekolah"}, []string{"Compass", "Ventela", "Bata", "Adidas", "Swallow"}, 20_000, 1_500_000},
+	{"Makanan & Minuman", []string{"Kopi Gayo 250gr", "Keripik Singkong", "Sambal Bawang", "Rendang Kemasan", "Teh Melati", "Madu Hutan"}, []string{"Kapal Api", "Indofood", "Sosro", "Maicih", "Bu Rudy"}, 8_000, 250_000},
+	{"Kecantikan", []string{"Serum Wajah", "Sunscreen SPF 50", "Lipstik Matte", "Facial Wash", "Masker Wajah"}, []string{"Wardah", "Somethinc", "Make Over", "Emina", "Azarine"}, 15_000, 350_000},
+	{"Rumah Tangga", []string{"Panci Set", "Wajan Anti Lengket", "Rak Piring", "Sprei Katun", "Ember Lipat", "Dispenser Sabun"}, []string{"Maspion", "Lion Star", "Kirin", "Oxone", "Shinpo"}, 15_000, 1_200_000},
+	{"Olahraga", []string{"Matras Yoga", "Raket Badminton", "Bola Futsal", "Dumbbell 5kg", "Jersey Bola"}, []string{"Yonex", "Li-Ning", "Specs", "Mitre", "Ortuseight"}, 20_000, 2_000_000},
+	{"Ibu & Bayi", []string{"Popok Bayi", "Botol Susu", "Gendongan Bayi", "Baju Bayi", "Tisu Basah"}, []string{"Mamypoko", "Pigeon", "Sweety", "Dialogue", "Cussons"}, 10_000, 800_000},
+	{"Otomotif", []string{"Helm Half Face", "Oli Mesin 1L", "Cover Motor", "Sarung Jok", "Lampu LED Motor"}, []string{"KYT", "INK", "Federal", "Shell", "Osram"}, 20_000, 1_000_000},
+}
+
+var (
+	shopPrefixes = []string{"Toko", "Grosir", "Pusat", "Rumah", "Gudang", "Warung", "Galeri"}
+	shopNames    = []string{"Budi", "Sari", "Wijaya", "Santoso", "Hidayat", "Lestari", "Kurniawan", "Pratama", "Siti", "Agus", "Dewi", "Rahmat", "Nusantara", "Sumber Rejeki", "Cahaya", "Mulia"}
+	shopSuffixes = []string{"Jaya", "Makmur", "Sentosa", "Abadi", "Berkah", "Sejahtera", "Mandiri", "Official Store", "Murah"}
+	streets      = []string{"Jl. Sudirman", "Jl. Merdeka", "Jl. Gatot Subroto", "Jl. Diponegoro", "Jl. Ahmad Yani", "Jl. Pahlawan", "Jl. Gajah Mada", "Jl. Hayam Wuruk"}
+	variants     = []string{"Original", "Premium", "Murah", "Terlaris", "Promo", "Garansi Resmi", "Import", "Lokal", "Ready Stock"}
+)
+
+// categoriesSeed inserts the catalogue categories that do not exist yet.
+func (s *Seed) categoriesSeed() {
+	var inserted int64
+
+	for _, c := range catalogue {
+		res, err := s.db.Exec(`
+			INSERT INTO product_categories (name)
+			SELECT $1
+			WHERE NOT EXISTS (SELECT 1 FROM product_categories WHERE name = $1 AND deleted_at IS NULL)
+		`, c.category)
+		if err != nil {
+			log.Error().Err(err).Str("category", c.category).Msg("Error creating category")
+			return
+		}
+
+		n, _ := res.RowsAffected()
+		inserted += n
+	}
+
+	log.Info().Int64("inserted", inserted).Msg("product_categories table seeded successfully")
+}
+
+// shopsSeed inserts total shops owned by total/3 sellers, about 1 in 5 of them verified.
+func (s *Seed) shopsSeed(total int) {
+	var (
+		f       = s.faker
+		columns = []string{
+			"id", "user_id", "name", "slug", "description", "terms", "phone", "email", "address", "city",
+			"province", "postal_code", "location", "timezone", "verified_at", "created_at", "updated_at",
+		}
+		owners = make([]string, max(total/3, 1))
+		now    = s.now
+	)
+
+	for i := range owners {
+		owners[i] = f.UUID()
+	}
+
+	err := s.copyIn("shops", columns, total, func(i int) []any {
+		var (
+			id        = f.UUID()
+			c         = cities[f.IntN(len(cities))]
+			name      = fmt.Sprintf("%s %s %s", f.RandomString(shopPrefixes), f.RandomString(shopNames), f.RandomString(shopSuffixes))
+			createdAt = f.DateRange(now.AddDate(-3, 0, 0), now)
+			verified  any
+		)
+
+		if f.IntN(5) == 0 {
+			verified = createdAt.Add(time.Duration(f.IntRange(1, 30*24)) * time.Hour)
+		}
+
+		// spread shops around the city center, up to ~10km away
+		lat := c.lat + f.Float64Range(-0.09, 0.09)
+		lng := c.lng + f.Float64Range(-0.09, 0.09)
+
+		return []any{
+			id,
+			owners[f.IntN(len(owners))],
+			name,
+			pkg.Slugify(name) + "-" + id[:8],
+			fmt.Sprintf("%s di %s. Melayani pengiriman ke seluruh Indonesia.", name, c.name),
+			"Barang yang sudah dibeli tidak dapat dikembalikan kecuali cacat produksi. Pesanan dikirim H+1 setelah pembayaran.",
+			"08" + f.Numerify("##########"),
+			strings.ToLower(strings.ReplaceAll(pkg.Slugify(name), "-", "")) + "@" + f.RandomString([]string{"gmail.com", "yahoo.co.id", "outlook.com"}),
+			fmt.Sprintf("%s No. %d", f.RandomString(streets), f.IntRange(1, 250)),
+			c.name,
+			c.province,
+			f.Numerify("#####"),
+			fmt.Sprintf("SRID=4326;POINT(%f %f)", lng, lat),
+			c.timezone,
+			verified,
+			createdAt,
+			createdAt,
+		}
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Error creating shops")
+		return
+	}
+
+	log.Info().Int("total", total).Msg("shops table seeded successfully")
+}
+
+// productsSeed inserts total products spread over the existing shops, in the
+// category matching their name, and starts the price history of each.
+func (s *Seed) productsSeed(total int) {
+	type category struct {
+		Id   string `db:"id"`
+		Name string `db:"name"`
+	}
+
+	var (
+		f          = s.faker
+		shops      = make([]string, 0)
+		categories = make([]category, 0)
+		now        = s.now
+	)
+
+	err := s.db.Select(&shops, `SELECT id FROM shops WHERE deleted_at IS NULL ORDER BY id`)
+	if err != nil {
+		log.Error().Err(err).Msg("Error selecting shops")
+		return
+	}
+
+	err = s.db.Select(&categories, `SELECT id, name FROM product_categories WHERE deleted_at IS NULL ORDER BY id`)
+	if err != nil {
+		log.Error().Err(err).Msg("Error selecting categories")
+		return
+	}
+
+	if len(shops) == 0 || len(categories) == 0 {
+		log.Error().Msg("Seed shops and categories before products")
+		return
+	}
+
+	// categories outside the catalogue get products of a random catalogue entry
+	entries := make([]int, len(categories))
+	for i, c := range categories {
+		entries[i] = f.IntN(len(catalogue))
+		for j, e := range catalogue {
+			if e.category == c.Name {
+				entries[i] = j
+				break
+			}
+		}
+	}
+
+	var (
+		columns = []string{
+			"id", "shop_id", "category_id", "name", "description", "image_url", "stock", "price",
+			"currency", "brand", "status", "created_at", "updated_at",
+		}
+		prices = make([][]any, 0, s.batch)
+	)
+
+	err = s.copyIn("products", columns, total, func(i int) []any {
+		var (
+			id        = f.UUID()
+			ci        = f.IntN(len(categories))
+			entry     = catalogue[entries[ci]]
+			brand     = f.RandomString(entry.brands)
+			name      = fmt.Sprintf("%s %s %s", brand, f.RandomString(entry.products), f.RandomString(variants))
+			price     = roundPrice(f.IntRange(entry.minPrice, entry.maxPrice))
+			createdAt = f.DateRange(now.AddDate(-2, 0, 0), now)
+			status    = "active"
+		)
+
+		switch n := f.IntN(20); {
+		case n == 0:
+			status = "draft"
+		case n == 1:
+			status = "archived"
+		}
+
+		prices = append(prices, []any{id, price, "IDR", createdAt})
+
+		return []any{
+			id,
+			shops[f.IntN(len(shops))],
+			categories[ci].Id,
+			name,
+			fmt.Sprintf("%s %s, %s. Dikirim dari gudang kami dalam 1x24 jam.", name, strings.ToLower(f.ProductFeature()), strings.ToLower(f.ProductMaterial())),
+			fmt.Sprintf("https://picsum.photos/seed/%s/600/600", id[:8]),
+			f.IntRange(0, 500),
+			price,
+			"IDR",
+			brand,
+			status,
+			createdAt,
+			createdAt,
+		}
+	}, func(tx *sql.Tx) error {
+		// the price history of a batch is copied in the same transaction
+		err := copyRows(tx, "product_prices", []string{"product_id", "price", "currency", "created_at"}, prices)
+		prices = prices[:0]
+		return err
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Error creating products")
+		return
+	}
+
+	log.Info().Int("total", total).Msg("products table seeded successfully")
+}
+
+// copyIn loads total rows built by row into table with COPY, committing
+// every s.batch rows. afterBatch runs in the transaction of each batch.
+func (s *Seed) copyIn(table string, columns []string, total int, row func(i int) []any, afterBatch ...func(tx *sql.Tx) error) error {
+	rows := make([][]any, 0, s.batch)
+
+	for done := 0; done < total; {
+		rows = rows[:0]
+		for len(rows) < s.batch && done+len(rows) < total {
+			rows = append(rows, row(done+len(rows)))
+		}
+
+		tx, err := s.db.BeginTx(context.Background(), nil)
+		if err != nil {
+			return err
+		}
+
+		if err = copyRows(tx, table, columns, rows); err == nil {
+			for _, fn := range afterBatch {
+				if err = fn(tx); err != nil {
+					break
+				}
+			}
+		}
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+
+		if err = tx.Commit(); err != nil {
+			return err
+		}
+
+		done += len(rows)
+		log.Info().Str("table", table).Int("done", done).Int("total", total).Msg("Seeding")
+	}
+
+	return nil
+}
+
+func copyRows(tx *sql.Tx, table string, columns []string, rows [][]any) error {
+	stmt, err := tx.Prepare(pq.CopyIn(table, columns...))
+	if err != nil {
+		return err
+	}
+
+	for _, r := range rows {
+		if _, err = stmt.Exec(r...); err != nil {
+			_ = stmt.Close()
+			return err
+		}
+	}
+
+	// flushes the buffered rows
+	if _, err = stmt.Exec(); err != nil {
+		_ = stmt.Close()
+		return err
+	}
+
+	return stmt.Close()
+}
+
+// roundPrice rounds to the 500 rupiah, like most listings.
+func roundPrice(p int) int {
+	return max((p+250)/500*500, 500)
+}
@@ -4,6 +4,7 @@ import (
 	"codebase-app/internal/adapter"
 	"context"
 	"os"
+	"time"
 
 	"github.com/brianvoe/gofakeit/v7"
 	"github.com/jmoiron/sqlx"
@@ -11,21 +12,40 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+// seedTime is the "now" seeded dates end at when the seed is fixed.
+var seedTime = time.Date(2024, time.September, 1, 0, 0, 0, 0, time.UTC)
+
 // Seed struct.
 type Seed struct {
-	db *sqlx.DB
+	db    *sqlx.DB
+	faker *gofakeit.Faker
+	batch int
+	// now ends the seeded date ranges
+	now time.Time
 }
 
-// NewSeed return a Seed with a pool of connection to a dabase.
-func newSeed(db *sqlx.DB) Seed {
+// NewSeed return a Seed with a pool of connection to a dabase. The same
+// non-zero seed always generates the same data, 0 picks a random one.
+func newSeed(db *sqlx.DB, seed uint64, batch int) Seed {
+	faker := gofakeit.New(seed)
+	gofakeit.GlobalFaker = faker
+
+	now := seedTime
+	if seed == 0 {
+		now = time.Now()
+	}
+
 	return Seed{
-		db: db,
+		db:    db,
+		faker: faker,
+		batch: max(batch, 1),
+		now:   now,
 	}
 }
 
-func Execute(db *sqlx.DB, table string, total int) {
-	seed := newSeed(db)
-	seed.run(table, total)
+func Execute(db *sqlx.DB, table string, total int, seed uint64, batch int) {
+	s := newSeed(db, seed, batch)
+	s.run(table, total)
 }
 
 // Run seeds.
@@ -36,6 +56,12 @@ func (s *Seed) run(table string, total int) {
 		s.rolesSeed()
 	case "users":
 		s.usersSeed(total)
+	case "categories":
+		s.categoriesSeed()
+	case "shops":
+		s.shopsSeed(total)
+	case "products":
+		s.productsSeed(total)
 	case "all":
 		s.rolesSeed()
 		s.usersSeed(total)
@@ -1,9 +1,13 @@
 package adapter
 
 import (
+	"codebase-app/internal/infrastructure/config"
+	"codebase-app/internal/module/ratelimit/ports"
 	"fmt"
 	"net/http"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	// import "codebase-app/internal/pkg/validator"
 
@@ -32,6 +36,9 @@ type Adapter struct {
 	ShopeefunPostgres *sqlx.DB
 	Validator         Validator // *validator.Validator
 	ShopeefunStorage  *s3.Client
+	RateLimitStore    ports.RateLimitStore // nil when rate limiting is off
+
+	draining atomic.Bool
 }
 
 func (a *Adapter) Sync(opts ...Option) {
@@ -45,7 +52,8 @@ func (a *Adapter) Unsync() error {
 	var errs []string
 
 	if a.RestServer != nil {
-		if err := a.RestServer.Shutdown(); err != nil {
+		// in-flight requests get the drain timeout to finish
+		if err := a.RestServer.ShutdownWithTimeout(time.Duration(config.Envs.Shutdown.DrainTimeout)); err != nil {
 			errs = append(errs, err.Error())
 		}
 		log.Info().Msg("Rest server disconnected")
@@ -0,0 +1,81 @@
+package adapter
+
+import (
+	"codebase-app/internal/infrastructure/config"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+type ComponentStatus struct {
+	Status    string `json:"status"` // "up" or "down"
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Check probes every driven adapter that was synced, concurrently and each
+// within timeout, and returns their status by name.
+func (a *Adapter) Check(ctx context.Context, timeout time.Duration) map[string]ComponentStatus {
+	checks := make(map[string]func(ctx context.Context) error)
+
+	if a.ShopeefunPostgres != nil {
+		checks["shopeefun_postgres"] = func(ctx context.Context) error {
+			return a.ShopeefunPostgres.PingContext(ctx)
+		}
+	}
+
+	if a.ShopeefunStorage != nil {
+		checks["shopeefun_storage"] = func(ctx context.Context) error {
+			_, err := a.ShopeefunStorage.HeadBucket(ctx, &s3.HeadBucketInput{
+				Bucket: aws.String(config.Envs.ShopeefunStorage.Bucket),
+			})
+			return err
+		}
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		statuses = make(map[string]ComponentStatus, len(checks))
+	)
+
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check func(ctx context.Context) error) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := check(ctx)
+
+			status := ComponentStatus{Status: "up", LatencyMs: time.Since(start).Milliseconds()}
+			if err != nil {
+				status.Status = "down"
+				status.Error = err.Error()
+			}
+
+			mu.Lock()
+			statuses[name] = status
+			mu.Unlock()
+		}(name, check)
+	}
+
+	wg.Wait()
+
+	return statuses
+}
+
+// SetDraining marks the server as shutting down, readiness checks fail from
+// then on so load balancers stop sending new requests.
+func (a *Adapter) SetDraining() {
+	a.draining.Store(true)
+}
+
+func (a *Adapter) Draining() bool {
+	return a.draining.Load()
+}
@@ -0,0 +1,25 @@
+package adapter
+
+import (
+	"codebase-app/internal/infrastructure/config"
+	"codebase-app/internal/module/ratelimit/repository"
+
+	"github.com/rs/zerolog/log"
+)
+
+// WithRateLimitStore picks the store of the rate limiter from
+// RATE_LIMIT_STORE, postgres needs WithShopeefunPostgres first. The store
+// stays nil when rate limiting is off.
+func WithRateLimitStore() Option {
+	return func(a *Adapter) {
+		switch config.Envs.RateLimit.Store {
+		case "memory":
+			a.RateLimitStore = repository.NewMemoryStore(config.Envs.RateLimit.MemoryMaxKeys)
+		case "postgres":
+			if a.ShopeefunPostgres == nil {
+				log.Fatal().Msg("Rate limit store postgres needs the Shopeefun Postgres adapter")
+			}
+			a.RateLimitStore = repository.NewPostgresStore(a.ShopeefunPostgres)
+		}
+	}
+}
@@ -25,22 +25,24 @@ func WithShopeefunPostgres() Option {
 		dbConnMaxLifetime := config.Envs.DB.ConnMaxLifetime
 
 		connectionString := "user=" + dbUser + " password=" + dbPassword + " host=" + dbHost + " port=" + dbPort + " dbname=" + dbName + " sslmode=" + dbSSLMode + " TimeZone=UTC"
-		db, err := sqlx.Connect("postgres", connectionString)
+		db, err := sqlx.Open("postgres", connectionString)
 		if err != nil {
 			log.Fatal().Err(err).Msg("Error connecting to Postgres")
 		}
 
 		db.SetMaxOpenConns(dbMaxPoolSize)
 		db.SetMaxIdleConns(dbMaxIdleConns)
-		db.SetConnMaxLifetime(time.Duration(dbConnMaxLifetime) * time.Second)
+		db.SetConnMaxLifetime(time.Duration(dbConnMaxLifetime))
 
-		// check connection
+		a.ShopeefunPostgres = db
+
+		// the pool reconnects on its own, until then /readyz reports it down
 		err = db.Ping()
 		if err != nil {
-			log.Fatal().Err(err).Msg("Error connecting to Shopeefun Postgres")
+			log.Error().Err(err).Msg("Shopeefun Postgres is not reachable yet")
+			return
 		}
 
-		a.ShopeefunPostgres = db
 		log.Info().Msg("Shopeefun Postgres connected")
 	}
 }
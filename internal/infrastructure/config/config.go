@@ -2,51 +2,103 @@ package config
 
 import (
 	"codebase-app/pkg/config"
+	"strings"
 	"sync"
 
 	"github.com/rs/zerolog/log"
 )
 
 var (
-	Envs *Config // Envs is global vars Config.
-	once sync.Once
+	Envs    *Config // Envs is global vars Config.
+	once    sync.Once
+	current *Configure // the Configure Envs was loaded with, used to reload
 )
 
 type Config struct {
 	App struct {
-		Name                    string `env:"APP_NAME"`
-		Environtment            string `env:"APP_ENV" env-default:"production"`
-		BaseURL                 string `env:"APP_BASE_URL" env-default:"http://localhost:3000"`
-		Port                    string `env:"APP_PORT"`
-		WSPort                  string `env:"WS_PORT"`
-		LogLevel                string `env:"APP_LOG_LEVEL" env-default:"debug"`
-		LogFile                 string `env:"APP_LOG_FILE" env-default:"./logs/app.log"`
-		LogFileWs               string `env:"APP_LOG_FILE_WS" env-default:"./logs/ws.log"`
-		LocalStoragePublicPath  string `env:"LOCAL_STORAGE_PUBLIC_PATH" env-default:"./storage/public"`
-		LocalStoragePrivatePath string `env:"LOCAL_STORAGE_PRIVATE_PATH" env-default:"./storage/private"`
+		Name                    string          `env:"APP_NAME"`
+		Environtment            string          `env:"APP_ENV" env-default:"production" validate:"oneof=development staging production"`
+		BaseURL                 string          `env:"APP_BASE_URL" env-default:"http://localhost:3000" validate:"url"`
+		Port                    string          `env:"APP_PORT" validate:"omitempty,numeric"`
+		WSPort                  string          `env:"WS_PORT"`
+		LogLevel                string          `env:"APP_LOG_LEVEL" env-default:"debug" validate:"oneof=trace debug info warn error fatal panic disabled"`
+		LogFile                 string          `env:"APP_LOG_FILE" env-default:"./logs/app.log"`
+		LogFileWs               string          `env:"APP_LOG_FILE_WS" env-default:"./logs/ws.log"`
+		LocalStoragePublicPath  string          `env:"LOCAL_STORAGE_PUBLIC_PATH" env-default:"./storage/public"`
+		LocalStoragePrivatePath string          `env:"LOCAL_STORAGE_PRIVATE_PATH" env-default:"./storage/private"`
+		BodyLimit               config.ByteSize `env:"APP_BODY_LIMIT" env-default:"4MiB" env-description:"largest request body accepted, e.g. 4MiB or 10MB" validate:"gt=0"`
+		ProxyHeader             string          `env:"APP_PROXY_HEADER" env-description:"header the load balancer sets to the client ip, e.g. X-Real-IP; empty uses the connection's address"`
+		TrustedProxies          []string        `env:"APP_TRUSTED_PROXIES" env-description:"comma separated ips or CIDRs of the load balancers whose APP_PROXY_HEADER and X-Forwarded-Proto are believed" validate:"dive,ip|cidr"`
 	}
 	DB struct {
-		ConnectionTimeout int `env:"DB_CONN_TIMEOUT" env-default:"30" env-description:"database timeout in seconds"`
-		MaxOpenCons       int `env:"DB_MAX_OPEN_CONS" env-default:"20" env-description:"database max open conn in seconds"`
-		MaxIdleCons       int `env:"DB_MAX_IdLE_CONS" env-default:"20" env-description:"database max idle conn in seconds"`
-		ConnMaxLifetime   int `env:"DB_CONN_MAX_LIFETIME" env-default:"0" env-description:"database conn max lifetime in seconds"`
+		ConnectionTimeout config.Duration `env:"DB_CONN_TIMEOUT" env-default:"30s" env-description:"database timeout" validate:"gte=0"`
+		MaxOpenCons       int             `env:"DB_MAX_OPEN_CONS" env-default:"20" env-description:"database max open conns" validate:"gte=0"`
+		MaxIdleCons       int             `env:"DB_MAX_IDLE_CONS" env-default:"20" env-description:"database max idle conns" validate:"gte=0"`
+		ConnMaxLifetime   config.Duration `env:"DB_CONN_MAX_LIFETIME" env-default:"0" env-description:"database conn max lifetime, 0 keeps conns forever" validate:"gte=0"`
+	}
+	Moderation struct {
+		ReportHideThreshold int `env:"MODERATION_REPORT_HIDE_THRESHOLD" env-default:"5" env-description:"number of abuse reports before content is hidden" validate:"gte=1"`
+	}
+	Worker struct {
+		ProductScheduleInterval    config.Duration `env:"WORKER_PRODUCT_SCHEDULE_INTERVAL" env-default:"1m" env-description:"product publish scheduler interval" validate:"gt=0"`
+		IdempotencyCleanupInterval config.Duration `env:"WORKER_IDEMPOTENCY_CLEANUP_INTERVAL" env-default:"1h" env-description:"expired idempotency keys cleanup interval" validate:"gt=0"`
+		RateLimitCleanupInterval   config.Duration `env:"WORKER_RATE_LIMIT_CLEANUP_INTERVAL" env-default:"5m" env-description:"expired rate limit counts cleanup interval" validate:"gt=0"`
+	}
+	Purge struct {
+		RetentionDays int `env:"PURGE_RETENTION_DAYS" env-default:"30" env-description:"days a soft-deleted row is kept before the purge command hard-deletes it" validate:"gte=0"`
+	}
+	Idempotency struct {
+		TTL         config.Duration `env:"IDEMPOTENCY_TTL" env-default:"24h" env-description:"how long an idempotency key and its response are kept" validate:"gt=0"`
+		LockTimeout config.Duration `env:"IDEMPOTENCY_LOCK_TIMEOUT" env-default:"1m" env-description:"time after which an unfinished request no longer holds its idempotency key" validate:"gt=0"`
+	}
+	Shutdown struct {
+		Timeout        config.Duration `env:"SHUTDOWN_TIMEOUT" env-default:"30s" env-description:"time the whole shutdown may take before it is cut short" validate:"gt=0"`
+		DrainTimeout   config.Duration `env:"SHUTDOWN_DRAIN_TIMEOUT" env-default:"20s" env-description:"time in-flight requests get to finish once the listener is closed" validate:"gte=0"`
+		ReadinessDelay config.Duration `env:"SHUTDOWN_READINESS_DELAY" env-default:"5s" env-description:"time /readyz fails before the listener is closed, so load balancers stop routing first" validate:"gte=0"`
+	}
+	Health struct {
+		CheckTimeout config.Duration `env:"HEALTH_CHECK_TIMEOUT" env-default:"2s" env-description:"time each readiness check of a dependency may take" validate:"gt=0"`
+	}
+	// RateLimit (but its store), Cors and APP_LOG_LEVEL are reloaded on
+	// SIGHUP or when the config file changes, see Watch.
+	RateLimit struct {
+		Store         string          `env:"RATE_LIMIT_STORE" env-default:"memory" env-description:"where request counts are kept: memory (per replica), postgres (shared by replicas) or none to turn rate limiting off" validate:"oneof=none memory postgres"`
+		MemoryMaxKeys int             `env:"RATE_LIMIT_MEMORY_MAX_KEYS" env-default:"100000" env-description:"clients the memory store counts at once, the oldest windows are dropped beyond it" validate:"gt=0"`
+		Window        config.Duration `env:"RATE_LIMIT_WINDOW" env-default:"1m" env-description:"window the budgets below are counted over" validate:"gt=0"`
+		WriteMax      int             `env:"RATE_LIMIT_WRITE_MAX" env-default:"60" env-description:"POST, PUT, PATCH and DELETE requests per window per ip, 0 is unlimited" validate:"gte=0"`
+		ReadMax       int             `env:"RATE_LIMIT_READ_MAX" env-default:"300" env-description:"GET requests per window per ip, 0 is unlimited" validate:"gte=0"`
+	}
+	Cors struct {
+		AllowOrigins     string `env:"CORS_ALLOW_ORIGINS" env-default:"*" env-description:"comma separated origins allowed to call the api, * allows any" validate:"required,origins"`
+		AllowHeaders     string `env:"CORS_ALLOW_HEADERS" env-default:"Origin,Content-Type,Accept,Content-Length,Accept-Language,Accept-Encoding,Connection,Authorization,If-Match,If-None-Match,Idempotency-Key,Traceparent,Tracestate,X-Request-ID,X-USER-ID" env-description:"comma separated request headers browsers may send" validate:"required"`
+		ExposeHeaders    string `env:"CORS_EXPOSE_HEADERS" env-default:"ETag,Idempotent-Replayed,Trace-Id,X-Request-ID,X-RateLimit-Limit,X-RateLimit-Remaining,X-RateLimit-Reset,Retry-After" env-description:"comma separated response headers browsers may read"`
+		AllowCredentials bool   `env:"CORS_ALLOW_CREDENTIALS" env-default:"false" env-description:"allow cookies and Authorization on cross-origin requests, needs explicit CORS_ALLOW_ORIGINS"`
+	}
+	Security struct {
+		HSTSMaxAge   config.Duration `env:"SECURITY_HSTS_MAX_AGE" env-default:"4320h" env-description:"how long browsers keep to https, sent on https requests only, 0 leaves the header out" validate:"gte=0"`
+		FrameOptions string          `env:"SECURITY_FRAME_OPTIONS" env-default:"DENY" env-description:"X-Frame-Options: DENY or SAMEORIGIN" validate:"oneof=DENY SAMEORIGIN"`
+	}
+	Tracing struct {
+		Exporter    string  `env:"TRACING_EXPORTER" env-default:"none" env-description:"span exporter: none, otlp (see OTEL_EXPORTER_OTLP_ENDPOINT), stdout or file" validate:"oneof=none otlp stdout file"`
+		File        string  `env:"TRACING_FILE" env-default:"./logs/traces.json" env-description:"file the file exporter appends spans to"`
+		SampleRatio float64 `env:"TRACING_SAMPLE_RATIO" env-default:"1" env-description:"share of new traces that are sampled, between 0 and 1" validate:"gte=0,lte=1"`
 	}
 	Guard struct {
-		JwtPrivateKey   string `env:"JWT_PRIVATE_KEY"`
-		JwtPrivateKeyWs string `env:"JWT_PRIVATE_KEY_WS"`
-		JwtWsExp        int    `env:"JWT_WS_EXP" env-default:"10"` // 10 seconds
+		JwtPrivateKey   string          `env:"JWT_PRIVATE_KEY" secret:"true" env-description:"signs tokens and private storage urls, required by the server"`
+		JwtPrivateKeyWs string          `env:"JWT_PRIVATE_KEY_WS" secret:"true"`
+		JwtWsExp        config.Duration `env:"JWT_WS_EXP" env-default:"10s" validate:"gt=0"`
 	}
 	ShopeefunPostgres struct {
-		Host     string `env:"SHOPEEFUN_POSTGRES_HOST" env-default:"localhost"`
-		Port     string `env:"SHOPEEFUN_POSTGRES_PORT" env-default:"5432"`
+		Host     string `env:"SHOPEEFUN_POSTGRES_HOST" env-default:"localhost" validate:"required"`
+		Port     string `env:"SHOPEEFUN_POSTGRES_PORT" env-default:"5432" validate:"numeric"`
 		Username string `env:"SHOPEEFUN_POSTGRES_USER" env-default:"postgres"`
-		Password string `env:"SHOPEEFUN_POSTGRES_PASSWORD" env-default:"postgres"`
-		Database string `env:"SHOPEEFUN_POSTGRES_DB" env-default:"venatronics"`
-		SslMode  string `env:"SHOPEEFUN_POSTGRES_SSL_MODE" env-default:"disable"`
+		Password string `env:"SHOPEEFUN_POSTGRES_PASSWORD" env-default:"postgres" secret:"true"`
+		Database string `env:"SHOPEEFUN_POSTGRES_DB" env-default:"venatronics" validate:"required"`
+		SslMode  string `env:"SHOPEEFUN_POSTGRES_SSL_MODE" env-default:"disable" validate:"oneof=disable allow prefer require verify-ca verify-full"`
 	}
 	ShopeefunStorage struct {
 		Key      string `env:"SHOPEEFUN_STORAGE_KEY"`
-		Secret   string `env:"SHOPEEFUN_STORAGE_SECRET"`
+		Secret   string `env:"SHOPEEFUN_STORAGE_SECRET" secret:"true"`
 		Endpoint string `env:"SHOPEEFUN_STORAGE_ENDPOINT"`
 		Region   string `env:"SHOPEEFUN_STORAGE_REGION"`
 		Bucket   string `env:"SHOPEEFUN_STORAGE_BUCKET"`
@@ -54,7 +106,7 @@ type Config struct {
 	Oauth struct {
 		Google struct {
 			ClientId     string `env:"GOOGLE_CLIENT_ID"`
-			ClientSecret string `env:"GOOGLE_CLIENT_SECRET"`
+			ClientSecret string `env:"GOOGLE_CLIENT_SECRET" secret:"true"`
 			RedirectURL  string `env:"GOOGLE_REDIRECT_URL"`
 		}
 	}
@@ -82,20 +134,67 @@ func Configuration(opts ...Option) *Configure {
 	return c
 }
 
-// Initialize will create instance of Configure.
+// Initialize will create instance of Configure. It exits when the
+// configuration cannot be read or is invalid.
 func (c *Configure) Initialize() {
 	once.Do(func() {
-		Envs = &Config{}
-		if err := config.Load(config.Opts{
-			Config:    Envs,
-			Paths:     []string{c.path},
-			Filenames: []string{c.filename},
-		}); err != nil {
+		cfg, err := c.load()
+		if err != nil {
 			log.Fatal().Err(err).Msg("get config error")
 		}
+
+		Envs = cfg
+		current = c
 	})
 }
 
+// load reads and validates a fresh Config.
+func (c *Configure) load() (*Config, error) {
+	cfg := &Config{}
+	if err := config.Load(config.Opts{
+		Config:    cfg,
+		Paths:     []string{c.path},
+		Filenames: []string{c.filename},
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := config.Validate(cfg); err != nil {
+		return nil, err
+	}
+
+	// browsers refuse credentials from a wildcard origin, fiber panics on it
+	if cfg.Cors.AllowCredentials && strings.Contains(cfg.Cors.AllowOrigins, "*") {
+		return nil, &config.ValidationError{
+			Problems: []string{"CORS_ALLOW_CREDENTIALS needs explicit CORS_ALLOW_ORIGINS, got \"*\""},
+		}
+	}
+
+	// without trusted proxies any client could set the header to any ip
+	if cfg.App.ProxyHeader != "" && len(cfg.App.TrustedProxies) == 0 {
+		return nil, &config.ValidationError{
+			Problems: []string{"APP_PROXY_HEADER needs APP_TRUSTED_PROXIES"},
+		}
+	}
+
+	return cfg, nil
+}
+
+// ValidateServer checks the settings only the server needs, so migrate,
+// seed and purge run without them.
+func (c *Config) ValidateServer() error {
+	var problems []string
+	if c.Guard.JwtPrivateKey == "" {
+		problems = append(problems, "JWT_PRIVATE_KEY is required")
+	}
+
+	if len(problems) > 0 {
+		return &config.ValidationError{Problems: problems}
+	}
+
+	return nil
+}
+
 // WithPath will assign to field path Configure.
 func WithPath(path string) Option {
 	return func(c *Configure) error {
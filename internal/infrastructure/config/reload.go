@@ -0,0 +1,133 @@
+package config
+
+import (
+	"codebase-app/pkg/config"
+	"context"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// how often Watch looks at the config file's modification time
+const watchInterval = 5 * time.Second
+
+var (
+	reloadMu  sync.Mutex
+	listeners []func(cfg *Config)
+)
+
+// applyReloadable copies the settings that can change while the server
+// runs from n. Everything else is read once at startup.
+func (c *Config) applyReloadable(n *Config) {
+	c.App.LogLevel = n.App.LogLevel
+	c.RateLimit.Window = n.RateLimit.Window
+	c.RateLimit.WriteMax = n.RateLimit.WriteMax
+	c.RateLimit.ReadMax = n.RateLimit.ReadMax
+	c.Cors = n.Cors
+}
+
+// OnReload registers fn to be called with Envs after a reload changed one
+// of the reloadable settings.
+func OnReload(fn func(cfg *Config)) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	listeners = append(listeners, fn)
+}
+
+// Reload reads the configuration again and applies its reloadable settings
+// to Envs. Other settings that changed are only logged, they take effect on
+// the next restart. An invalid configuration is rejected and Envs is kept.
+func Reload() error {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	next, err := current.load()
+	if err != nil {
+		return err
+	}
+
+	applied := *Envs
+	applied.applyReloadable(next)
+	if restart := config.Diff(&applied, next); len(restart) > 0 {
+		log.Warn().Strs("settings", restart).Msg("config::Reload - Changed settings need a restart to take effect")
+	}
+
+	changed := config.Diff(Envs, &applied)
+	if len(changed) == 0 {
+		log.Info().Msg("config::Reload - No reloadable setting changed")
+		return nil
+	}
+
+	Envs.applyReloadable(next)
+	log.Info().Strs("settings", changed).Msg("config::Reload - Configuration reloaded")
+
+	for _, fn := range listeners {
+		fn(Envs)
+	}
+
+	return nil
+}
+
+// Watch reloads the configuration on SIGHUP and when the config file
+// changes, until ctx is done. Logs are rotated on SIGUSR1.
+func Watch(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	var (
+		files   = current.files()
+		modTime = lastModified(files)
+	)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			reload("SIGHUP")
+		case <-ticker.C:
+			// compared for equality so a file restored from an older copy counts too
+			if t := lastModified(files); !t.Equal(modTime) {
+				modTime = t
+				reload("file changed")
+			}
+		}
+	}
+}
+
+func reload(trigger string) {
+	if err := Reload(); err != nil {
+		log.Error().Err(err).Str("trigger", trigger).Msg("config::Watch - Failed to reload configuration, keeping the current one")
+	}
+}
+
+// files lists the files Initialize reads, see config.Load.
+func (c *Configure) files() []string {
+	files := []string{filepath.Join(c.path, ".env")}
+	if c.filename != ".env" {
+		files = append(files, filepath.Join(c.path, c.filename))
+	}
+
+	return files
+}
+
+func lastModified(files []string) time.Time {
+	var last time.Time
+	for _, f := range files {
+		if fi, err := os.Stat(f); err == nil && fi.ModTime().After(last) {
+			last = fi.ModTime()
+		}
+	}
+
+	return last
+}
@@ -0,0 +1,111 @@
+// Package lifecycle runs the shutdown of the server in one place: it waits
+// for a termination signal, then runs the registered hooks in reverse order
+// of registration, like defers, so what started last stops first.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+type hook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+type Manager struct {
+	mu      sync.Mutex
+	hooks   []hook
+	timeout time.Duration
+}
+
+// New returns a manager whose hooks must all finish within timeout.
+func New(timeout time.Duration) *Manager {
+	return &Manager{timeout: timeout}
+}
+
+// OnShutdown registers fn to run on shutdown. The context of fn expires
+// with the overall shutdown timeout.
+func (m *Manager) OnShutdown(name string, fn func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.hooks = append(m.hooks, hook{name: name, fn: fn})
+}
+
+// Go runs a background worker until shutdown. Its hook cancels the context
+// of fn and waits for fn to return.
+func (m *Manager) Go(name string, fn func(ctx context.Context)) {
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		done        = make(chan struct{})
+	)
+
+	go func() {
+		defer close(done)
+		fn(ctx)
+	}()
+
+	m.OnShutdown(name, func(shutdownCtx context.Context) error {
+		cancel()
+
+		select {
+		case <-done:
+			return nil
+		case <-shutdownCtx.Done():
+			return fmt.Errorf("%s did not stop in time: %w", name, shutdownCtx.Err())
+		}
+	})
+}
+
+// Wait blocks until the process receives SIGINT or SIGTERM, then shuts down.
+func (m *Manager) Wait() error {
+	signals := []os.Signal{os.Interrupt, syscall.SIGTERM}
+	if runtime.GOOS == "windows" {
+		signals = []os.Signal{os.Interrupt}
+	}
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, signals...)
+	sig := <-quit
+	signal.Stop(quit)
+
+	log.Info().Str("signal", sig.String()).Msg("lifecycle::Wait - Shutting down ...")
+
+	return m.Shutdown()
+}
+
+// Shutdown runs every hook, even after one of them failed, and returns
+// their errors joined.
+func (m *Manager) Shutdown() error {
+	m.mu.Lock()
+	hooks := m.hooks
+	m.hooks = nil
+	m.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+
+	var errs []error
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		h := hooks[i]
+
+		log.Debug().Str("hook", h.name).Msg("lifecycle::Shutdown - Running shutdown hook")
+		if err := h.fn(ctx); err != nil {
+			log.Error().Err(err).Str("hook", h.name).Msg("lifecycle::Shutdown - Shutdown hook failed")
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -11,8 +11,9 @@ import (
 	"gopkg.in/natefinch/lumberjack.v2"
 )
 
-// InitializeLogger will set logging format.
-func InitializeLogger(stage string, filename string, logLevel zerolog.Level) {
+// InitializeLogger will set logging format. The returned closer closes the
+// log file, call it last on shutdown.
+func InitializeLogger(stage string, filename string, logLevel zerolog.Level) io.Closer {
 
 	var (
 		lumberjackLogger = &lumberjack.Logger{
@@ -29,21 +30,19 @@ func InitializeLogger(stage string, filename string, logLevel zerolog.Level) {
 	if stage == "production" {
 		logger = zerolog.New(lumberjackLogger).With().Timestamp().Caller().Logger().Level(zerolog.InfoLevel)
 	} else {
-		logger = zerolog.New(mw).With().Timestamp().Caller().Logger().Level(logLevel)
+		logger = zerolog.New(mw).With().Timestamp().Caller().Logger()
 	}
 	log.Logger = logger
 
-	q := make(chan os.Signal, 1)
+	// the global level can be changed while running, see config.Reload
+	zerolog.SetGlobalLevel(logLevel)
+
+	// log.Ctx(ctx) falls back to the global logger for contexts without one
+	zerolog.DefaultContextLogger = &log.Logger
+
+	// SIGHUP reloads the configuration, see config.Watch
 	c := make(chan os.Signal, 1)
-	signal.Notify(q, syscall.SIGQUIT, syscall.SIGTERM, syscall.SIGINT, os.Interrupt)
-	signal.Notify(c, syscall.SIGHUP)
-	go func() {
-		for {
-			<-q
-			lumberjackLogger.Close()
-			log.Info().Msg("Closing logs ...")
-		}
-	}()
+	signal.Notify(c, syscall.SIGUSR1)
 	go func() {
 		for {
 			<-c
@@ -53,4 +52,6 @@ func InitializeLogger(stage string, filename string, logLevel zerolog.Level) {
 			log.Info().Msg("Rotating logs ...")
 		}
 	}()
+
+	return lumberjackLogger
 }
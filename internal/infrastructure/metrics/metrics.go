@@ -0,0 +1,87 @@
+// Package metrics holds the Prometheus collectors of the service, exposed on
+// GET /metrics.
+package metrics
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const namespace = "shopeefun"
+
+// Registry only holds the collectors below, the Go runtime and the process,
+// not whatever libraries register on the default one.
+var Registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by route template, method and status code.",
+	}, []string{"route", "method", "status"})
+
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route template, method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	ProductsCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "products_created_total",
+		Help:      "Products created.",
+	})
+
+	ProductStatusChanges = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "product_status_changes_total",
+		Help:      "Product status transitions by new status, made by sellers or the schedule worker.",
+	}, []string{"status"})
+
+	ProductSalesCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "product_sales_created_total",
+		Help:      "Scheduled sale prices created.",
+	})
+
+	ShopsCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "shops_created_total",
+		Help:      "Shops created.",
+	})
+
+	ReviewsCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reviews_created_total",
+		Help:      "Product reviews created.",
+	})
+
+	RateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limited_requests_total",
+		Help:      "Requests rejected with 429 by rate limit group: auth, write or read.",
+	}, []string{"group"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPRequestDuration,
+		ProductsCreated,
+		ProductStatusChanges,
+		ProductSalesCreated,
+		ShopsCreated,
+		ReviewsCreated,
+		RateLimited,
+	)
+}
+
+// RegisterDB exports the connection pool stats of db (open, in use and idle
+// connections, waits) as go_sql_* gauges labelled with name.
+func RegisterDB(name string, db *sqlx.DB) {
+	Registry.MustRegister(collectors.NewDBStatsCollector(db.DB, name))
+}
@@ -103,6 +103,8 @@ func (l *localstorage) extensionFromMimeType(mimeType string) string {
 		return "jpg"
 	case "image/png":
 		return "png"
+	case "application/pdf":
+		return "pdf"
 	default:
 		return ""
 	}
@@ -110,7 +112,7 @@ func (l *localstorage) extensionFromMimeType(mimeType string) string {
 
 func (l *localstorage) isAcceptableMimeType(mimeType string) bool {
 	switch mimeType {
-	case "image/jpeg", "image/png":
+	case "image/jpeg", "image/png", "application/pdf":
 		return true
 	default:
 		return false
@@ -13,7 +13,7 @@ func AuthMiddleware(c *fiber.Ctx) error {
 
 	// If the cookie is not set, return an unauthorized status
 	if cookie == "" {
-		log.Error().Msg("middleware::AuthMiddleware - Unauthorized [Cookie not set]")
+		log.Ctx(c.UserContext()).Error().Msg("middleware::AuthMiddleware - Unauthorized [Cookie not set]")
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 			"message": "Unauthorized",
 			"success": false,
@@ -23,7 +23,7 @@ func AuthMiddleware(c *fiber.Ctx) error {
 	// Parse the JWT string and store the result in `claims`
 	claims, err := jwthandler.ParseTokenString(cookie)
 	if err != nil {
-		log.Error().Err(err).Msg("middleware::AuthMiddleware - Error while parsing token")
+		log.Ctx(c.UserContext()).Error().Err(err).Msg("middleware::AuthMiddleware - Error while parsing token")
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"message": "Bad request",
 			"success": false,
@@ -16,7 +16,7 @@ func AuthBearer(c *fiber.Ctx) error {
 
 	// If the cookie is not set, return an unauthorized status
 	if AccessToken == "" {
-		log.Error().Msg("middleware::AuthMiddleware - Unauthorized [Header not set]")
+		log.Ctx(c.UserContext()).Error().Msg("middleware::AuthMiddleware - Unauthorized [Header not set]")
 		return c.Status(fiber.StatusUnauthorized).JSON(unauthorizedResponse)
 	}
 
@@ -28,7 +28,7 @@ func AuthBearer(c *fiber.Ctx) error {
 	// Parse the JWT string and store the result in `claims`
 	claims, err := jwthandler.ParseTokenString(AccessToken)
 	if err != nil {
-		log.Error().Err(err).Any("payload", AccessToken).Msg("middleware::AuthMiddleware - Error while parsing token")
+		log.Ctx(c.UserContext()).Error().Err(err).Any("payload", AccessToken).Msg("middleware::AuthMiddleware - Error while parsing token")
 		return c.Status(fiber.StatusUnauthorized).JSON(unauthorizedResponse)
 	}
 
@@ -31,7 +31,7 @@ func AuthRole(authorizedRoles []string) func(*fiber.Ctx) error {
 			AuthorizedRole: authorizedRoles,
 		}
 
-		log.Warn().Any("payload", payload).Msg("middleware::AuthRole - Unauthorized")
+		log.Ctx(c.UserContext()).Warn().Any("payload", payload).Msg("middleware::AuthRole - Unauthorized")
 		return c.Status(fiber.StatusForbidden).JSON(forbiddenResponse)
 	}
 }
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"codebase-app/internal/adapter"
+	"codebase-app/internal/infrastructure/config"
+	"codebase-app/internal/module/idempotency/entity"
+	"codebase-app/internal/module/idempotency/repository"
+	"codebase-app/internal/module/idempotency/service"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/response"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+const HeaderIdempotencyKey = "Idempotency-Key"
+
+// Idempotency makes a POST safe to retry. When the client sends an
+// Idempotency-Key, the first request runs and its response is stored per
+// user and key; a retry with the same body gets that response replayed, a
+// different body or a retry while the first one still runs gets 409.
+// It must run after UserIdHeader.
+func Idempotency() fiber.Handler {
+	var (
+		repo        = repository.NewIdempotencyRepository(adapter.Adapters.ShopeefunPostgres)
+		service     = service.NewIdempotencyService(repo)
+		ttl         = time.Duration(config.Envs.Idempotency.TTL)
+		lockTimeout = time.Duration(config.Envs.Idempotency.LockTimeout)
+	)
+
+	return func(c *fiber.Ctx) error {
+		key := c.Get(HeaderIdempotencyKey)
+		if key == "" {
+			return c.Next()
+		}
+
+		if len(key) > 255 {
+			log.Ctx(c.UserContext()).Warn().Str("key", key).Msg("middleware::Idempotency - Key too long")
+			return c.Status(fiber.StatusBadRequest).JSON(response.Error("Idempotency-Key maksimal 255 karakter"))
+		}
+
+		var (
+			ctx = c.UserContext()
+			l   = GetLocals(c)
+			sum = sha256.Sum256([]byte(c.Method() + " " + c.Path() + "\n" + string(c.Body())))
+			req = &entity.AcquireRequest{
+				UserId:      l.UserId,
+				Key:         key,
+				Fingerprint: hex.EncodeToString(sum[:]),
+				TTL:         ttl,
+				LockTimeout: lockTimeout,
+			}
+		)
+
+		record, err := service.Begin(ctx, req)
+		if err != nil {
+			code, errs := errmsg.Errors[error](err)
+			return c.Status(code).JSON(response.Error(errs))
+		}
+
+		if record != nil {
+			log.Ctx(ctx).Info().Str("user_id", req.UserId).Str("key", key).Msg("middleware::Idempotency - Replaying stored response")
+			c.Set("Idempotent-Replayed", "true")
+			if record.ContentType != nil {
+				c.Set(fiber.HeaderContentType, *record.ContentType)
+			}
+			return c.Status(*record.StatusCode).Send(record.ResponseBody)
+		}
+
+		release := &entity.ReleaseRequest{UserId: req.UserId, Key: key}
+
+		if err := c.Next(); err != nil {
+			_ = service.Release(ctx, release)
+			return err
+		}
+
+		// server errors are not stored, the client may retry them with the same key
+		status := c.Response().StatusCode()
+		if status >= fiber.StatusInternalServerError {
+			_ = service.Release(ctx, release)
+			return nil
+		}
+
+		_ = service.Complete(ctx, &entity.CompleteRequest{
+			UserId:       req.UserId,
+			Key:          key,
+			Fingerprint:  req.Fingerprint,
+			StatusCode:   status,
+			ContentType:  string(c.Response().Header.ContentType()),
+			ResponseBody: c.Response().Body(),
+		})
+
+		return nil
+	}
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"codebase-app/pkg"
+	"codebase-app/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+// IfMatch requires an If-Match header holding the ETag the client last
+// read, and stores its version in the locals for an optimistic update.
+func IfMatch(c *fiber.Ctx) error {
+	header := c.Get(fiber.HeaderIfMatch)
+	if header == "" {
+		log.Ctx(c.UserContext()).Warn().Msg("middleware::IfMatch - Precondition required [Header not set]")
+		return c.Status(fiber.StatusPreconditionRequired).JSON(response.Error("Header If-Match wajib diisi"))
+	}
+
+	version, ok := pkg.ParseETag(header)
+	if !ok {
+		log.Ctx(c.UserContext()).Warn().Str("if_match", header).Msg("middleware::IfMatch - Invalid ETag")
+		return c.Status(fiber.StatusPreconditionFailed).JSON(response.Error("Header If-Match tidak valid"))
+	}
+
+	c.Locals("if_match", version)
+
+	return c.Next()
+}
+
+// SendETagged sends body as JSON with an ETag of its version and content,
+// or 304 when If-None-Match already holds it. Values that change without a
+// new version, like sale prices, ratings or the open state, and bodies that
+// differ per caller get a different ETag.
+func SendETagged(c *fiber.Ctx, version int, body any) error {
+	data, err := c.App().Config().JSONEncoder(body)
+	if err != nil {
+		return err
+	}
+
+	etag := pkg.FormatBodyETag(version, data)
+	c.Set(fiber.HeaderETag, etag)
+
+	if pkg.ETagMatches(c.Get(fiber.HeaderIfNoneMatch), etag) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return c.Status(fiber.StatusOK).Send(data)
+}
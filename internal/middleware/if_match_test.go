@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSendETagged changes the body without a new version, like a sale
+// starting, and expects a fresh 200 instead of a stale 304.
+func TestSendETagged(t *testing.T) {
+	price := "100000"
+
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		return SendETagged(c, 3, fiber.Map{"price": price})
+	})
+
+	get := func(ifNoneMatch string) (int, string) {
+		req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set(fiber.HeaderIfNoneMatch, ifNoneMatch)
+		}
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		return resp.StatusCode, resp.Header.Get(fiber.HeaderETag)
+	}
+
+	code, etag := get("")
+	assert.Equal(t, fiber.StatusOK, code)
+
+	code, _ = get(etag)
+	assert.Equal(t, fiber.StatusNotModified, code)
+
+	price = "80000"
+	code, changed := get(etag)
+	assert.Equal(t, fiber.StatusOK, code)
+	assert.NotEqual(t, etag, changed)
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"codebase-app/internal/infrastructure/metrics"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Metrics records the count and latency of every request, labelled by the
+// route template (e.g. /products/:id) so ids do not explode the series.
+func Metrics(c *fiber.Ctx) error {
+	if c.Path() == "/metrics" {
+		return c.Next()
+	}
+
+	start := time.Now()
+	err := c.Next()
+
+	status := c.Response().StatusCode()
+	if err != nil {
+		// the error handler has not written the response yet
+		status = fiber.StatusInternalServerError
+		if e, ok := err.(*fiber.Error); ok {
+			status = e.Code
+		}
+	}
+
+	// requests no route matched end in the fallback handler, mounted on "/"
+	route := c.Route().Path
+	if status == fiber.StatusNotFound && route == "/" {
+		route = "unmatched"
+	}
+
+	var (
+		method = c.Method()
+		code   = strconv.Itoa(status)
+	)
+
+	metrics.HTTPRequests.WithLabelValues(route, method, code).Inc()
+	metrics.HTTPRequestDuration.WithLabelValues(route, method, code).Observe(time.Since(start).Seconds())
+
+	return err
+}
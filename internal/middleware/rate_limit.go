@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"codebase-app/internal/adapter"
+	"codebase-app/internal/infrastructure/config"
+	"codebase-app/internal/infrastructure/metrics"
+	"codebase-app/internal/module/ratelimit/entity"
+	"codebase-app/pkg/response"
+	"math"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+// Rate limit groups, each with its own budget per client.
+const (
+	RateLimitWrite = "write"
+	RateLimitRead  = "read"
+	// RateLimitByMethod counts POST, PUT, PATCH and DELETE as write and
+	// everything else as read, for a whole route group.
+	RateLimitByMethod = ""
+)
+
+type rateLimitBudget struct {
+	window time.Duration
+	max    map[string]int
+}
+
+var (
+	rateLimitBudgets    atomic.Pointer[rateLimitBudget]
+	rateLimitBudgetOnce sync.Once
+)
+
+func storeRateLimitBudget(cfg *config.Config) {
+	rateLimitBudgets.Store(&rateLimitBudget{
+		window: time.Duration(cfg.RateLimit.Window),
+		max: map[string]int{
+			RateLimitWrite: cfg.RateLimit.WriteMax,
+			RateLimitRead:  cfg.RateLimit.ReadMax,
+		},
+	})
+}
+
+// RateLimit limits the requests of a client in group to the group's
+// RATE_LIMIT_*_MAX per RATE_LIMIT_WINDOW. Clients are told apart by ip, read
+// from APP_PROXY_HEADER behind a trusted load balancer.
+// When the store fails the request is let through.
+func RateLimit(group string) fiber.Handler {
+	rateLimitBudgetOnce.Do(func() {
+		storeRateLimitBudget(config.Envs)
+		config.OnReload(storeRateLimitBudget)
+	})
+
+	return func(c *fiber.Ctx) error {
+		store := adapter.Adapters.RateLimitStore
+		if store == nil {
+			return c.Next()
+		}
+
+		g := group
+		if g == RateLimitByMethod {
+			g = RateLimitRead
+			switch c.Method() {
+			case fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete:
+				g = RateLimitWrite
+			}
+		}
+
+		budget := rateLimitBudgets.Load()
+		limit := budget.max[g]
+		if limit == 0 || c.Method() == fiber.MethodOptions {
+			return c.Next()
+		}
+
+		var (
+			ctx = c.UserContext()
+			// X-USER-ID is not authenticated, a client could rotate it for
+			// a fresh budget on every request
+			key = g + ":ip:" + c.IP()
+		)
+
+		hit, err := store.Hit(ctx, &entity.HitRequest{Key: key, Window: budget.window})
+		if err != nil {
+			log.Ctx(ctx).Warn().Err(err).Str("key", key).Msg("middleware::RateLimit - Store failed, request let through")
+			return c.Next()
+		}
+
+		reset := int(math.Ceil(time.Until(hit.ResetAt).Seconds()))
+		c.Set("X-RateLimit-Limit", strconv.Itoa(limit))
+		c.Set("X-RateLimit-Remaining", strconv.Itoa(limit-min(hit.Hits, limit)))
+		c.Set("X-RateLimit-Reset", strconv.Itoa(reset))
+
+		if hit.Hits > limit {
+			metrics.RateLimited.WithLabelValues(g).Inc()
+			log.Ctx(ctx).Warn().Str("key", key).Int("hits", hit.Hits).Msg("middleware::RateLimit - Too many requests")
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(reset))
+			return c.Status(fiber.StatusTooManyRequests).JSON(response.Error("Terlalu banyak permintaan, coba lagi nanti"))
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"codebase-app/internal/adapter"
+	"codebase-app/internal/infrastructure/config"
+	"codebase-app/internal/module/ratelimit/repository"
+	pkgconfig "codebase-app/pkg/config"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRateLimitIgnoresUserHeader rotates X-USER-ID, which anyone can set,
+// and still runs out of the ip's budget.
+func TestRateLimitIgnoresUserHeader(t *testing.T) {
+	config.Envs = &config.Config{}
+	config.Envs.RateLimit.Window = pkgconfig.Duration(time.Minute)
+	config.Envs.RateLimit.ReadMax = 2
+	adapter.Adapters = &adapter.Adapter{RateLimitStore: repository.NewMemoryStore(100)}
+	storeRateLimitBudget(config.Envs)
+
+	app := fiber.New()
+	app.Get("/", RateLimit(RateLimitRead), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	codes := make([]int, 0, 3)
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+		req.Header.Set("X-USER-ID", uuid.NewString())
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		codes = append(codes, resp.StatusCode)
+	}
+
+	assert.Equal(t, []int{fiber.StatusNoContent, fiber.StatusNoContent, fiber.StatusTooManyRequests}, codes)
+}
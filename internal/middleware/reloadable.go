@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"codebase-app/internal/infrastructure/config"
+	"sync/atomic"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+)
+
+// Reloadable is a middleware that is rebuilt whenever the configuration
+// is reloaded, fiber cannot replace a handler once it is mounted.
+type Reloadable struct {
+	build   func(cfg *config.Config) fiber.Handler
+	handler atomic.Pointer[fiber.Handler]
+}
+
+// NewReloadable builds the middleware from config.Envs now and again after
+// every reload.
+func NewReloadable(build func(cfg *config.Config) fiber.Handler) *Reloadable {
+	r := &Reloadable{build: build}
+	r.reload(config.Envs)
+	config.OnReload(r.reload)
+
+	return r
+}
+
+func (r *Reloadable) reload(cfg *config.Config) {
+	h := r.build(cfg)
+	r.handler.Store(&h)
+}
+
+// Handle runs the current middleware.
+func (r *Reloadable) Handle(c *fiber.Ctx) error {
+	return (*r.handler.Load())(c)
+}
+
+// Cors answers preflights and sets the CORS headers from the CORS_*
+// settings.
+func Cors(cfg *config.Config) fiber.Handler {
+	return cors.New(cors.Config{
+		AllowOrigins:     cfg.Cors.AllowOrigins,
+		AllowMethods:     "GET,POST,PUT,DELETE,PATCH,OPTIONS,HEAD",
+		AllowHeaders:     cfg.Cors.AllowHeaders,
+		ExposeHeaders:    cfg.Cors.ExposeHeaders,
+		AllowCredentials: cfg.Cors.AllowCredentials,
+	})
+}
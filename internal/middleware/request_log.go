@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"codebase-app/internal/infrastructure/tracing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+const HeaderRequestId = "X-Request-ID"
+
+// RequestLog takes the X-Request-ID of the request, or generates one, and
+// echoes it in the response. It attaches a logger carrying the request and
+// trace ids to c.UserContext(), so every line logged with log.Ctx(ctx) while
+// serving the request can be correlated, and writes one access log line per
+// request. Mount it after Tracing.
+func RequestLog(c *fiber.Ctx) error {
+	requestId := c.Get(HeaderRequestId)
+	if !validRequestId(requestId) {
+		requestId = uuid.NewString()
+	}
+	c.Set(HeaderRequestId, requestId)
+
+	var (
+		ctx    = c.UserContext()
+		logger = log.With().Str("request_id", requestId)
+	)
+
+	if traceId := tracing.TraceId(ctx); traceId != "" {
+		logger = logger.Str("trace_id", traceId)
+	}
+
+	l := logger.Logger()
+	c.SetUserContext(l.WithContext(ctx))
+
+	start := time.Now()
+	err := c.Next()
+
+	status := c.Response().StatusCode()
+	if err != nil {
+		// the error handler has not written the response yet
+		status = fiber.StatusInternalServerError
+		if e, ok := err.(*fiber.Error); ok {
+			status = e.Code
+		}
+	}
+
+	level := zerolog.InfoLevel
+	switch {
+	case status >= fiber.StatusInternalServerError:
+		level = zerolog.ErrorLevel
+	case status >= fiber.StatusBadRequest:
+		level = zerolog.WarnLevel
+	}
+
+	userId, _ := c.Locals("user_id").(string)
+
+	l.WithLevel(level).
+		Err(err).
+		Str("method", c.Method()).
+		Str("path", c.Path()).
+		Str("route", c.Route().Path).
+		Int("status", status).
+		Int("bytes", len(c.Response().Body())).
+		Dur("latency", time.Since(start)).
+		Str("user_id", userId).
+		Str("ip", c.IP()).
+		Str("ua", c.Get(fiber.HeaderUserAgent)).
+		Msg("middleware::RequestLog - Request served")
+
+	return err
+}
+
+// validRequestId accepts ids of up to 128 printable ASCII characters, so a
+// client cannot inject anything into the logs.
+func validRequestId(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"codebase-app/internal/infrastructure/config"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SecurityHeaders sets the headers that keep browsers from sniffing
+// content types and framing responses, and tells them to keep to https.
+// HSTS is only sent on https requests, X-Forwarded-Proto counts.
+func SecurityHeaders(cfg *config.Config) fiber.Handler {
+	var (
+		frameOptions = cfg.Security.FrameOptions
+		hsts         string
+	)
+
+	if maxAge := time.Duration(cfg.Security.HSTSMaxAge); maxAge > 0 {
+		hsts = "max-age=" + strconv.FormatInt(int64(maxAge.Seconds()), 10) + "; includeSubDomains"
+	}
+
+	return func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+		c.Set(fiber.HeaderXFrameOptions, frameOptions)
+		c.Set(fiber.HeaderReferrerPolicy, "strict-origin-when-cross-origin")
+		if hsts != "" && c.Protocol() == "https" {
+			c.Set(fiber.HeaderStrictTransportSecurity, hsts)
+		}
+
+		return c.Next()
+	}
+}
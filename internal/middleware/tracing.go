@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"codebase-app/internal/infrastructure/tracing"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("codebase-app/internal/middleware")
+
+// Tracing starts a server span per request, continuing the trace of an
+// incoming traceparent header. Handlers pass c.UserContext() down so
+// repository queries become child spans. Every response gets a Trace-Id
+// header and JSON error bodies a "trace_id" field.
+func Tracing(c *fiber.Ctx) error {
+	var (
+		parent = otel.GetTextMapPropagator().Extract(c.UserContext(), propagation.HeaderCarrier(c.GetReqHeaders()))
+		method = c.Method()
+	)
+
+	ctx, span := tracer.Start(parent, method, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(
+		semconv.HTTPRequestMethodKey.String(method),
+		semconv.URLPath(c.Path()),
+		semconv.ClientAddress(c.IP()),
+		semconv.UserAgentOriginal(c.Get(fiber.HeaderUserAgent)),
+	))
+	defer span.End()
+
+	traceId := tracing.TraceId(ctx)
+	c.SetUserContext(ctx)
+	c.Set("Trace-Id", traceId)
+
+	err := c.Next()
+
+	// the route template is only known once the router matched it
+	route := c.Route().Path
+	span.SetName(method + " " + route)
+	span.SetAttributes(semconv.HTTPRoute(route))
+
+	status := c.Response().StatusCode()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+	if status >= fiber.StatusInternalServerError {
+		span.SetStatus(codes.Error, http.StatusText(status))
+	}
+
+	if status >= fiber.StatusBadRequest {
+		addTraceId(c, traceId)
+	}
+
+	return nil
+}
+
+// addTraceId adds "trace_id" to a JSON object error body, so a reported
+// error can be found in the traces and logs.
+func addTraceId(c *fiber.Ctx, traceId string) {
+	body := c.Response().Body()
+	if len(body) == 0 || body[0] != '{' {
+		return
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return
+	}
+
+	fields["trace_id"], _ = json.Marshal(traceId)
+
+	newBody, err := json.Marshal(fields)
+	if err != nil {
+		return
+	}
+
+	c.Response().SetBodyRaw(newBody)
+}
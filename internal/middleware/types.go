@@ -8,6 +8,8 @@ import (
 type Locals struct {
 	UserId string
 	Role   string
+	// IfMatch is the version sent in If-Match, see the IfMatch middleware.
+	IfMatch int
 }
 
 func GetLocals(c *fiber.Ctx) *Locals {
@@ -16,7 +18,11 @@ func GetLocals(c *fiber.Ctx) *Locals {
 	if ok {
 		l.UserId = userId
 	} else {
-		log.Warn().Msg("middleware::Locals-GetLocals failed to get user_id from locals")
+		log.Ctx(c.UserContext()).Warn().Msg("middleware::Locals-GetLocals failed to get user_id from locals")
+	}
+
+	if version, ok := c.Locals("if_match").(int); ok {
+		l.IfMatch = version
 	}
 
 	return &l
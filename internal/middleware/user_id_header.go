@@ -1,7 +1,10 @@
 package middleware
 
 import (
+	"codebase-app/pkg/response"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 )
 
@@ -13,11 +16,35 @@ func UserIdHeader(c *fiber.Ctx) error {
 	}
 
 	if userId == "" {
-		log.Error().Msg("middleware::UserIdHeader - Unauthorized [Header not set]")
+		log.Ctx(c.UserContext()).Error().Msg("middleware::UserIdHeader - Unauthorized [Header not set]")
 		return c.Status(fiber.StatusUnauthorized).JSON(unauthorizedResponse)
 	}
 
-	c.Locals("user_id", userId)
+	return setUserId(c, userId)
+}
+
+// OptionalUserIdHeader stores X-USER-ID in the locals when it is sent, for
+// public routes that show more to an authenticated owner.
+func OptionalUserIdHeader(c *fiber.Ctx) error {
+	if userId := c.Get("X-USER-ID"); userId != "" {
+		return setUserId(c, userId)
+	}
+
+	return c.Next()
+}
+
+// setUserId stores the user id in its canonical form, anything but a uuid
+// is rejected before it reaches a query.
+func setUserId(c *fiber.Ctx, userId string) error {
+	id, err := uuid.Parse(userId)
+	if err != nil {
+		log.Ctx(c.UserContext()).Warn().Str("user_id", userId).Msg("middleware::UserIdHeader - Invalid X-USER-ID")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(map[string][]string{
+			"X-USER-ID": {"X-USER-ID harus berupa uuid."},
+		}))
+	}
+
+	c.Locals("user_id", id.String())
 
 	return c.Next()
 }
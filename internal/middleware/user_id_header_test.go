@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserIdHeader(t *testing.T) {
+	app := fiber.New()
+	echo := func(c *fiber.Ctx) error {
+		userId, _ := c.Locals("user_id").(string)
+		return c.SendString(userId)
+	}
+	app.Get("/required", UserIdHeader, echo)
+	app.Get("/optional", OptionalUserIdHeader, echo)
+
+	tests := []struct {
+		path, userId string
+		code         int
+		body         string
+	}{
+		{"/required", "", fiber.StatusUnauthorized, ""},
+		{"/required", "not-a-uuid", fiber.StatusBadRequest, ""},
+		{"/required", "84095313-F3DC-4529-B869-24BB5C77C1A4", fiber.StatusOK, "84095313-f3dc-4529-b869-24bb5c77c1a4"},
+		{"/optional", "", fiber.StatusOK, ""},
+		{"/optional", "1 OR 1=1", fiber.StatusBadRequest, ""},
+		{"/optional", "84095313-f3dc-4529-b869-24bb5c77c1a4", fiber.StatusOK, "84095313-f3dc-4529-b869-24bb5c77c1a4"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(fiber.MethodGet, tt.path, nil)
+		if tt.userId != "" {
+			req.Header.Set("X-USER-ID", tt.userId)
+		}
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, tt.code, resp.StatusCode, "%s %q", tt.path, tt.userId)
+
+		if tt.code == fiber.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			assert.Equal(t, tt.body, string(body))
+		}
+	}
+}
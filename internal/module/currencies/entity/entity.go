@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type UpsertCurrencyRequest struct {
+	Code string `params:"code" validate:"iso4217" db:"code"`
+	// Rate is the value of one unit of Code in the base currency (IDR).
+	Rate decimal.Decimal `json:"rate" validate:"required,gt=0" db:"rate"`
+}
+
+type Currency struct {
+	Code      string          `json:"code" db:"code"`
+	Rate      decimal.Decimal `json:"rate" db:"rate"`
+	UpdatedAt time.Time       `json:"updated_at" db:"updated_at"`
+}
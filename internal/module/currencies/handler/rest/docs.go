@@ -0,0 +1,21 @@
+package handler
+
+import (
+	"codebase-app/internal/module/currencies/entity"
+	"codebase-app/pkg/openapi"
+)
+
+// Operations documents the routes of Register.
+var Operations = []openapi.Operation{
+	{
+		Handler:  (*currencyHandler).GetCurrencies,
+		Summary:  "List the supported currencies",
+		Response: []entity.Currency{},
+	},
+	{
+		Handler:  (*currencyHandler).UpsertCurrency,
+		Summary:  "Create or update a currency",
+		Request:  entity.UpsertCurrencyRequest{},
+		Response: entity.Currency{},
+	},
+}
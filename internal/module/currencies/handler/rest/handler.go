@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"codebase-app/internal/adapter"
+	"codebase-app/internal/middleware"
+	"codebase-app/internal/module/currencies/entity"
+	"codebase-app/internal/module/currencies/ports"
+	"codebase-app/internal/module/currencies/repository"
+	"codebase-app/internal/module/currencies/service"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/response"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+type currencyHandler struct {
+	service ports.CurrencyService
+}
+
+func NewCurrencyHandler() *currencyHandler {
+	var (
+		handler = new(currencyHandler)
+		repo    = repository.NewCurrencyRepository(adapter.Adapters.ShopeefunPostgres)
+		service = service.NewCurrencyService(repo)
+	)
+	handler.service = service
+
+	return handler
+}
+
+func (h *currencyHandler) Register(router fiber.Router) {
+	router.Get("/currencies", h.GetCurrencies)
+	router.Put("/currencies/:code", middleware.AuthBearer, middleware.AuthRole([]string{"admin"}), h.UpsertCurrency)
+}
+
+func (h *currencyHandler) UpsertCurrency(c *fiber.Ctx) error {
+	var (
+		req = new(entity.UpsertCurrencyRequest)
+		ctx = c.UserContext()
+		v   = adapter.Adapters.Validator
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("handler::UpsertCurrency - Parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	// iso4217 only accepts upper case codes
+	req.Code = strings.ToUpper(c.Params("code"))
+
+	if err := v.Validate(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::UpsertCurrency - Validate request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.UpsertCurrency(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *currencyHandler) GetCurrencies(c *fiber.Ctx) error {
+	var ctx = c.UserContext()
+
+	resp, err := h.service.GetCurrencies(ctx)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
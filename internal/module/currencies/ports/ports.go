@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"codebase-app/internal/module/currencies/entity"
+	"context"
+)
+
+type CurrencyRepository interface {
+	UpsertCurrency(ctx context.Context, req *entity.UpsertCurrencyRequest) (*entity.Currency, error)
+	GetCurrencies(ctx context.Context) ([]entity.Currency, error)
+}
+
+type CurrencyService interface {
+	UpsertCurrency(ctx context.Context, req *entity.UpsertCurrencyRequest) (*entity.Currency, error)
+	GetCurrencies(ctx context.Context) ([]entity.Currency, error)
+}
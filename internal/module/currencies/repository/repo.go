@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"codebase-app/internal/module/currencies/entity"
+	"codebase-app/internal/module/currencies/ports"
+	"codebase-app/pkg/database"
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+var _ ports.CurrencyRepository = &currencyRepository{}
+
+type currencyRepository struct {
+	db *database.DB
+}
+
+func NewCurrencyRepository(db *sqlx.DB) *currencyRepository {
+	return &currencyRepository{
+		db: database.New(db),
+	}
+}
+
+func (r *currencyRepository) UpsertCurrency(ctx context.Context, req *entity.UpsertCurrencyRequest) (*entity.Currency, error) {
+	var resp = new(entity.Currency)
+
+	query := `
+		INSERT INTO currencies (code, rate)
+		VALUES (?, ?)
+		ON CONFLICT (code) DO UPDATE
+		SET rate = EXCLUDED.rate, updated_at = NOW()
+		RETURNING code, rate, updated_at
+	`
+
+	err := r.db.QueryRowxContext(ctx, r.db.Rebind(query), req.Code, req.Rate).StructScan(resp)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::UpsertCurrency - Failed to upsert currency")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *currencyRepository) GetCurrencies(ctx context.Context) ([]entity.Currency, error) {
+	var resp = make([]entity.Currency, 0)
+
+	query := `
+		SELECT code, rate, updated_at
+		FROM currencies
+		ORDER BY code ASC
+	`
+
+	err := r.db.SelectContext(ctx, &resp, query)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("repository::GetCurrencies - Failed to get currencies")
+		return nil, err
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"codebase-app/internal/module/currencies/entity"
+	"codebase-app/internal/module/currencies/ports"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/types"
+	"context"
+
+	"github.com/rs/zerolog/log"
+)
+
+var _ ports.CurrencyService = &currencyService{}
+
+type currencyService struct {
+	repo ports.CurrencyRepository
+}
+
+func NewCurrencyService(repo ports.CurrencyRepository) *currencyService {
+	return &currencyService{
+		repo: repo,
+	}
+}
+
+func (s *currencyService) UpsertCurrency(ctx context.Context, req *entity.UpsertCurrencyRequest) (*entity.Currency, error) {
+	// every rate is relative to the base currency, so its own rate is fixed
+	if req.Code == types.DefaultCurrency {
+		log.Ctx(ctx).Warn().Any("payload", req).Msg("service::UpsertCurrency - Base currency rate is fixed")
+		return nil, errmsg.NewCustomErrors(422, errmsg.WithErrors("code", "Kurs mata uang dasar tidak dapat diubah"))
+	}
+
+	return s.repo.UpsertCurrency(ctx, req)
+}
+
+func (s *currencyService) GetCurrencies(ctx context.Context) ([]entity.Currency, error) {
+	return s.repo.GetCurrencies(ctx)
+}
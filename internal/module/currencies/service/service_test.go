@@ -0,0 +1,39 @@
+package service
+
+import (
+	"codebase-app/internal/module/currencies/entity"
+	"codebase-app/internal/module/currencies/ports"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	ports.CurrencyRepository
+
+	upserted []string
+}
+
+func (r *fakeRepo) UpsertCurrency(_ context.Context, req *entity.UpsertCurrencyRequest) (*entity.Currency, error) {
+	r.upserted = append(r.upserted, req.Code)
+	return &entity.Currency{Code: req.Code, Rate: req.Rate}, nil
+}
+
+func TestUpsertCurrencyRejectsBaseCurrency(t *testing.T) {
+	repo := new(fakeRepo)
+	s := NewCurrencyService(repo)
+
+	_, err := s.UpsertCurrency(context.Background(), &entity.UpsertCurrencyRequest{Code: "IDR", Rate: decimal.NewFromInt(2)})
+
+	var custom *errmsg.CustomError
+	require.ErrorAs(t, err, &custom)
+	assert.Equal(t, 422, custom.Code)
+
+	_, err = s.UpsertCurrency(context.Background(), &entity.UpsertCurrencyRequest{Code: "USD", Rate: decimal.NewFromInt(16000)})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"USD"}, repo.upserted)
+}
@@ -0,0 +1,48 @@
+package entity
+
+import "time"
+
+type AcquireRequest struct {
+	UserId      string
+	Key         string
+	Fingerprint string
+
+	TTL         time.Duration
+	LockTimeout time.Duration
+}
+
+// Record is a stored key, StatusCode is nil while its first request is
+// still being processed.
+type Record struct {
+	Fingerprint  string  `db:"fingerprint"`
+	StatusCode   *int    `db:"status_code"`
+	ContentType  *string `db:"content_type"`
+	ResponseBody []byte  `db:"response_body"`
+}
+
+func (r *Record) Completed() bool {
+	return r.StatusCode != nil
+}
+
+type AcquireResponse struct {
+	// Acquired is true when this request owns the key and must run.
+	Acquired bool
+	// Record is the existing key when it is not acquired, nil if it
+	// disappeared in between.
+	Record *Record
+}
+
+type CompleteRequest struct {
+	UserId      string
+	Key         string
+	Fingerprint string
+
+	StatusCode   int
+	ContentType  string
+	ResponseBody []byte
+}
+
+type ReleaseRequest struct {
+	UserId string
+	Key    string
+}
@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"codebase-app/internal/module/idempotency/entity"
+	"context"
+)
+
+type IdempotencyRepository interface {
+	Acquire(ctx context.Context, req *entity.AcquireRequest) (*entity.AcquireResponse, error)
+	Complete(ctx context.Context, req *entity.CompleteRequest) error
+	Release(ctx context.Context, req *entity.ReleaseRequest) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+type IdempotencyService interface {
+	Begin(ctx context.Context, req *entity.AcquireRequest) (*entity.Record, error)
+	Complete(ctx context.Context, req *entity.CompleteRequest) error
+	Release(ctx context.Context, req *entity.ReleaseRequest) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"codebase-app/internal/module/idempotency/entity"
+	"codebase-app/internal/module/idempotency/ports"
+	"codebase-app/pkg/database"
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+var _ ports.IdempotencyRepository = &idempotencyRepository{}
+
+type idempotencyRepository struct {
+	db *database.DB
+}
+
+func NewIdempotencyRepository(db *sqlx.DB) *idempotencyRepository {
+	return &idempotencyRepository{
+		db: database.New(db),
+	}
+}
+
+func (r *idempotencyRepository) Acquire(ctx context.Context, req *entity.AcquireRequest) (*entity.AcquireResponse, error) {
+	var resp = new(entity.AcquireResponse)
+
+	// the primary key serializes concurrent duplicates: only one insert wins,
+	// the others see the conflict. Expired keys, and keys whose request died
+	// before completing, are taken over.
+	query := `
+		INSERT INTO idempotency_keys (user_id, key, fingerprint, expires_at)
+		VALUES (?, ?, ?, NOW() + make_interval(secs => ?))
+		ON CONFLICT (user_id, key) DO UPDATE
+		SET
+			fingerprint = EXCLUDED.fingerprint,
+			status_code = NULL,
+			content_type = NULL,
+			response_body = NULL,
+			locked_at = NOW(),
+			created_at = NOW(),
+			expires_at = EXCLUDED.expires_at
+		WHERE
+			idempotency_keys.expires_at <= NOW()
+			OR (
+				idempotency_keys.status_code IS NULL
+				AND idempotency_keys.locked_at <= NOW() - make_interval(secs => ?)
+			)
+		RETURNING TRUE
+	`
+
+	err := r.db.QueryRowxContext(ctx, r.db.Rebind(query),
+		req.UserId,
+		req.Key,
+		req.Fingerprint,
+		req.TTL.Seconds(),
+		req.LockTimeout.Seconds()).Scan(&resp.Acquired)
+	if err == nil {
+		return resp, nil
+	}
+	if err != sql.ErrNoRows {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::Acquire - Failed to acquire idempotency key")
+		return nil, err
+	}
+
+	query = `
+		SELECT fingerprint, status_code, content_type, response_body
+		FROM idempotency_keys
+		WHERE user_id = ? AND key = ?
+	`
+
+	record := new(entity.Record)
+	err = r.db.GetContext(ctx, record, r.db.Rebind(query), req.UserId, req.Key)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// released by the request holding it in the meantime
+			return resp, nil
+		}
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::Acquire - Failed to get idempotency key")
+		return nil, err
+	}
+
+	resp.Record = record
+	return resp, nil
+}
+
+func (r *idempotencyRepository) Complete(ctx context.Context, req *entity.CompleteRequest) error {
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = ?, content_type = ?, response_body = ?
+		WHERE user_id = ? AND key = ? AND fingerprint = ? AND status_code IS NULL
+	`
+
+	_, err := r.db.ExecContext(ctx, r.db.Rebind(query),
+		req.StatusCode,
+		req.ContentType,
+		req.ResponseBody,
+		req.UserId,
+		req.Key,
+		req.Fingerprint)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", req.UserId).Str("key", req.Key).Msg("repository::Complete - Failed to store response")
+		return err
+	}
+
+	return nil
+}
+
+func (r *idempotencyRepository) Release(ctx context.Context, req *entity.ReleaseRequest) error {
+	query := `DELETE FROM idempotency_keys WHERE user_id = ? AND key = ? AND status_code IS NULL`
+
+	_, err := r.db.ExecContext(ctx, r.db.Rebind(query), req.UserId, req.Key)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::Release - Failed to release idempotency key")
+		return err
+	}
+
+	return nil
+}
+
+func (r *idempotencyRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= NOW()`)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("repository::DeleteExpired - Failed to delete expired idempotency keys")
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
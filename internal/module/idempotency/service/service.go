@@ -0,0 +1,55 @@
+package service
+
+import (
+	"codebase-app/internal/module/idempotency/entity"
+	"codebase-app/internal/module/idempotency/ports"
+	"codebase-app/pkg/errmsg"
+	"context"
+)
+
+var _ ports.IdempotencyService = &idempotencyService{}
+
+type idempotencyService struct {
+	repo ports.IdempotencyRepository
+}
+
+func NewIdempotencyService(repo ports.IdempotencyRepository) *idempotencyService {
+	return &idempotencyService{
+		repo: repo,
+	}
+}
+
+// Begin claims the key for a request. It returns nil when the request must
+// run, or the stored record when its response has to be replayed.
+func (s *idempotencyService) Begin(ctx context.Context, req *entity.AcquireRequest) (*entity.Record, error) {
+	res, err := s.repo.Acquire(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Acquired {
+		return nil, nil
+	}
+
+	record := res.Record
+	switch {
+	case record != nil && record.Fingerprint != req.Fingerprint:
+		return nil, errmsg.NewCustomErrors(409, errmsg.WithMessage("Idempotency-Key sudah digunakan untuk permintaan yang berbeda"))
+	case record == nil || !record.Completed():
+		return nil, errmsg.NewCustomErrors(409, errmsg.WithMessage("Permintaan dengan Idempotency-Key yang sama sedang diproses, coba lagi nanti"))
+	}
+
+	return record, nil
+}
+
+func (s *idempotencyService) Complete(ctx context.Context, req *entity.CompleteRequest) error {
+	return s.repo.Complete(ctx, req)
+}
+
+func (s *idempotencyService) Release(ctx context.Context, req *entity.ReleaseRequest) error {
+	return s.repo.Release(ctx, req)
+}
+
+func (s *idempotencyService) DeleteExpired(ctx context.Context) (int64, error) {
+	return s.repo.DeleteExpired(ctx)
+}
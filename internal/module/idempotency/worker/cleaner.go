@@ -0,0 +1,61 @@
+package worker
+
+import (
+	"codebase-app/internal/adapter"
+	"codebase-app/internal/module/idempotency/ports"
+	"codebase-app/internal/module/idempotency/repository"
+	"codebase-app/internal/module/idempotency/service"
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+type cleaner struct {
+	service  ports.IdempotencyService
+	interval time.Duration
+}
+
+// NewCleaner creates the worker that deletes expired idempotency keys.
+func NewCleaner(interval time.Duration) *cleaner {
+	var (
+		repo    = repository.NewIdempotencyRepository(adapter.Adapters.ShopeefunPostgres)
+		service = service.NewIdempotencyService(repo)
+	)
+
+	return &cleaner{
+		service:  service,
+		interval: interval,
+	}
+}
+
+// Start runs the cleaner until ctx is cancelled.
+func (w *cleaner) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	log.Info().Dur("interval", w.interval).Msg("worker::cleaner - Idempotency key cleaner started")
+
+	for {
+		w.run(ctx)
+
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("worker::cleaner - Idempotency key cleaner stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *cleaner) run(ctx context.Context) {
+	deleted, err := w.service.DeleteExpired(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("worker::cleaner - Failed to delete expired idempotency keys")
+		return
+	}
+
+	if deleted > 0 {
+		log.Info().Int64("deleted", deleted).Msg("worker::cleaner - Expired idempotency keys deleted")
+	}
+}
@@ -1,6 +1,9 @@
 package entity
 
-import "codebase-app/pkg/types"
+import (
+	"codebase-app/pkg/types"
+	"time"
+)
 
 type CreateProductCategoriesRequest struct {
 	Name string `json:"name" validate:"required" db:"name"`
@@ -15,20 +18,46 @@ type GetProductCategoriesRequest struct {
 }
 
 type GetProductCategoriesResponse struct {
-	Name string `json:"name" db:"name"`
+	Name    string `json:"name" db:"name"`
+	Version int    `json:"-" db:"version"`
 }
 
 type DeleteProductCategoriesRequest struct {
 	Id string `validate:"uuid" db:"id"`
+	// ReassignTo moves the products of the category to another one before
+	// deleting it, without it a category still in use cannot be deleted.
+	ReassignTo string `query:"reassign_to" validate:"omitempty,uuid,nefield=Id"`
+}
+
+type RestoreProductCategoriesRequest struct {
+	Id string `params:"id" validate:"uuid" db:"id"`
 }
 
+// UpdateProductCategoriesRequest is a JSON Merge Patch (RFC 7396).
 type UpdateProductCategoriesRequest struct {
-	Id   string `params:"id" validate:"uuid" db:"id"`
-	Name string `json:"name" validate:"required" db:"name"`
+	Id   string              `params:"id" validate:"uuid" db:"id"`
+	Name types.Patch[string] `json:"name" validate:"omitempty,min=1" db:"name"`
+
+	// Version is the one sent in If-Match, the update fails when it is stale.
+	Version int `db:"version"`
+}
+
+func (r *UpdateProductCategoriesRequest) CostumValidation() (int, map[string][]string) {
+	errors := types.NotNull(map[string]interface{ IsNull() bool }{
+		"name": r.Name,
+	})
+
+	if len(errors) > 0 {
+		return 400, errors
+	}
+
+	errors = nil
+	return 0, errors
 }
 
 type UpdateProductCategoriesResponse struct {
-	Id string `json:"id" db:"id"`
+	Id      string `json:"id" db:"id"`
+	Version int    `json:"-" db:"version"`
 }
 
 type ProductCategoriesRequest struct {
@@ -55,3 +84,35 @@ type ProductCategoriesResponse struct {
 	Items []ProductCategoriesItem `json:"items"`
 	Meta  types.Meta              `json:"meta"`
 }
+
+type TrashRequest struct {
+	Page     int `query:"page" validate:"required"`
+	Paginate int `query:"paginate" validate:"required"`
+}
+
+func (r *TrashRequest) SetDefault() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Paginate < 1 {
+		r.Paginate = 10
+	}
+}
+
+type TrashItem struct {
+	Id        string    `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	DeletedAt time.Time `json:"deleted_at" db:"deleted_at"`
+}
+
+type TrashResponse struct {
+	Items []TrashItem `json:"items"`
+	Meta  types.Meta  `json:"meta"`
+}
+
+// PurgeRequest hard-deletes the categories soft-deleted before Before that
+// no product points at anymore.
+type PurgeRequest struct {
+	Before time.Time
+}
@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"codebase-app/pkg/validator"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateProductCategoriesRequestName(t *testing.T) {
+	v := validator.NewValidator()
+
+	tests := []struct {
+		name    string
+		body    string
+		invalid bool
+	}{
+		{"absent", `{}`, false},
+		{"set", `{"name":"Pakaian"}`, false},
+		{"empty", `{"name":""}`, true},
+		{"null", `{"name":null}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := UpdateProductCategoriesRequest{Id: "9aa56858-974a-4c5a-9aa8-0fcce63430f7"}
+			require.NoError(t, json.Unmarshal([]byte(tt.body), &req))
+
+			_, errs := req.CostumValidation()
+			err := v.Validate(&req)
+
+			assert.Equal(t, tt.invalid, err != nil || len(errs) > 0, "validate: %v, costum: %v", err, errs)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"codebase-app/internal/module/product-categories/entity"
+	"codebase-app/pkg/openapi"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Operations documents the routes of Register.
+var Operations = []openapi.Operation{
+	{
+		Handler:  (*productCategoriesHandler).GetProductCategoriess,
+		Summary:  "List categories",
+		Request:  entity.ProductCategoriesRequest{},
+		Response: entity.ProductCategoriesResponse{},
+	},
+	{
+		Handler:  (*productCategoriesHandler).GetTrash,
+		Summary:  "List deleted categories",
+		Request:  entity.TrashRequest{},
+		Response: entity.TrashResponse{},
+	},
+	{
+		Handler:  (*productCategoriesHandler).CreateProductCategories,
+		Summary:  "Create a category",
+		Request:  entity.CreateProductCategoriesRequest{},
+		Response: entity.CreateProductCategoriesResponse{},
+		Status:   fiber.StatusCreated,
+	},
+	{
+		Handler:     (*productCategoriesHandler).GetProductCategories,
+		Summary:     "Get a category",
+		Description: "Answers 304 when If-None-Match matches the ETag.",
+		Request:     entity.GetProductCategoriesRequest{},
+		Response:    entity.GetProductCategoriesResponse{},
+	},
+	{
+		Handler: (*productCategoriesHandler).DeleteProductCategories,
+		Summary: "Delete a category",
+		Request: entity.DeleteProductCategoriesRequest{},
+	},
+	{
+		Handler: (*productCategoriesHandler).RestoreProductCategories,
+		Summary: "Restore a deleted category",
+		Request: entity.RestoreProductCategoriesRequest{},
+	},
+	{
+		Handler:  (*productCategoriesHandler).UpdateProductCategories,
+		Summary:  "Update a category",
+		Request:  entity.UpdateProductCategoriesRequest{},
+		Response: entity.UpdateProductCategoriesResponse{},
+	},
+}
@@ -7,6 +7,7 @@ import (
 	"codebase-app/internal/module/product-categories/ports"
 	"codebase-app/internal/module/product-categories/repository"
 	"codebase-app/internal/module/product-categories/service"
+	"codebase-app/pkg"
 	"codebase-app/pkg/errmsg"
 	"codebase-app/pkg/response"
 
@@ -31,26 +32,28 @@ func NewProductCategoriesHandler() *productCategoriesHandler {
 
 func (h *productCategoriesHandler) Register(router fiber.Router) {
 	router.Get("/categories", middleware.UserIdHeader, h.GetProductCategoriess)
+	router.Get("/categories/trash", middleware.UserIdHeader, h.GetTrash)
 	router.Post("/category", middleware.UserIdHeader, h.CreateProductCategories)
 	router.Get("/category/:id", h.GetProductCategories)
 	router.Delete("/category/:id", middleware.UserIdHeader, h.DeleteProductCategories)
-	router.Patch("/category/:id", middleware.UserIdHeader, h.UpdateProductCategories)
+	router.Post("/category/:id/restore", middleware.UserIdHeader, h.RestoreProductCategories)
+	router.Patch("/category/:id", middleware.UserIdHeader, middleware.IfMatch, h.UpdateProductCategories)
 }
 
 func (h *productCategoriesHandler) CreateProductCategories(c *fiber.Ctx) error {
 	var (
 		req = new(entity.CreateProductCategoriesRequest)
-		ctx = c.Context()
+		ctx = c.UserContext()
 		v   = adapter.Adapters.Validator
 	)
 
 	if err := c.BodyParser(req); err != nil {
-		log.Warn().Err(err).Msg("handler::ProductCategories - Parse request body")
+		log.Ctx(ctx).Warn().Err(err).Msg("handler::ProductCategories - Parse request body")
 		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
 	}
 
 	if err := v.Validate(req); err != nil {
-		log.Warn().Err(err).Any("payload", req).Msg("handler::CreateProductCategories - Validate request body")
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::CreateProductCategories - Validate request body")
 		code, errs := errmsg.Errors(err, req)
 		return c.Status(code).JSON(response.Error(errs))
 	}
@@ -68,14 +71,14 @@ func (h *productCategoriesHandler) CreateProductCategories(c *fiber.Ctx) error {
 func (h *productCategoriesHandler) GetProductCategories(c *fiber.Ctx) error {
 	var (
 		req = new(entity.GetProductCategoriesRequest)
-		ctx = c.Context()
+		ctx = c.UserContext()
 		v   = adapter.Adapters.Validator
 	)
 
 	req.Id = c.Params("id")
 
 	if err := v.Validate(req); err != nil {
-		log.Warn().Err(err).Any("payload", req).Msg("handler::GetProductCategories - Validate request body")
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::GetProductCategories - Validate request body")
 		code, errs := errmsg.Errors(err, req)
 		return c.Status(code).JSON(response.Error(errs))
 	}
@@ -86,20 +89,25 @@ func (h *productCategoriesHandler) GetProductCategories(c *fiber.Ctx) error {
 		return c.Status(code).JSON(response.Error(errs))
 	}
 
-	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+	return middleware.SendETagged(c, resp.Version, response.Success(resp, ""))
 }
 
 func (h *productCategoriesHandler) DeleteProductCategories(c *fiber.Ctx) error {
 	var (
 		req = new(entity.DeleteProductCategoriesRequest)
-		ctx = c.Context()
+		ctx = c.UserContext()
 		v   = adapter.Adapters.Validator
 	)
 
+	if err := c.QueryParser(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("handler::DeleteProductCategories - Parse request query")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
 	req.Id = c.Params("id")
 
 	if err := v.Validate(req); err != nil {
-		log.Warn().Err(err).Any("payload", req).Msg("handler::DeleteProductCategories - Validate request body")
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::DeleteProductCategories - Validate request body")
 		code, errs := errmsg.Errors(err, req)
 		return c.Status(code).JSON(response.Error(errs))
 	}
@@ -116,48 +124,56 @@ func (h *productCategoriesHandler) DeleteProductCategories(c *fiber.Ctx) error {
 func (h *productCategoriesHandler) UpdateProductCategories(c *fiber.Ctx) error {
 	var (
 		req = new(entity.UpdateProductCategoriesRequest)
-		ctx = c.Context()
+		ctx = c.UserContext()
 		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
 	)
 
 	if err := c.BodyParser(req); err != nil {
-		log.Warn().Err(err).Msg("handler::UpdateShop - Parse request body")
+		log.Ctx(ctx).Warn().Err(err).Msg("handler::UpdateShop - Parse request body")
 		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
 	}
 
 	req.Id = c.Params("id")
+	req.Version = l.IfMatch
 
 	if err := v.Validate(req); err != nil {
-		log.Warn().Err(err).Any("payload", req).Msg("handler::UpdateProductCategories - Validate request body")
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::UpdateProductCategories - Validate request body")
 		code, errs := errmsg.Errors(err, req)
 		return c.Status(code).JSON(response.Error(errs))
 	}
 
+	if code, errs := req.CostumValidation(); code != 0 {
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
 	resp, err := h.service.UpdateProductCategories(ctx, req)
 	if err != nil {
 		code, errs := errmsg.Errors[error](err)
 		return c.Status(code).JSON(response.Error(errs))
 	}
 
+	c.Set(fiber.HeaderETag, pkg.FormatETag(resp.Version))
+
 	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
 }
 
 func (h *productCategoriesHandler) GetProductCategoriess(c *fiber.Ctx) error {
 	var (
 		req = new(entity.ProductCategoriesRequest)
-		ctx = c.Context()
+		ctx = c.UserContext()
 		v   = adapter.Adapters.Validator
 	)
 
 	if err := c.QueryParser(req); err != nil {
-		log.Warn().Err(err).Msg("handler::GetProductCategories - Parse request query")
+		log.Ctx(ctx).Warn().Err(err).Msg("handler::GetProductCategories - Parse request query")
 		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
 	}
 
 	req.SetDefault()
 
 	if err := v.Validate(req); err != nil {
-		log.Warn().Err(err).Any("payload", req).Msg("handler::GetProductCategories - Validate request body")
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::GetProductCategories - Validate request body")
 		code, errs := errmsg.Errors(err, req)
 		return c.Status(code).JSON(response.Error(errs))
 	}
@@ -171,3 +187,55 @@ func (h *productCategoriesHandler) GetProductCategoriess(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
 
 }
+
+func (h *productCategoriesHandler) GetTrash(c *fiber.Ctx) error {
+	var (
+		req = new(entity.TrashRequest)
+		ctx = c.UserContext()
+		v   = adapter.Adapters.Validator
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("handler::GetTrash - Parse request query")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.SetDefault()
+
+	if err := v.Validate(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::GetTrash - Validate request query")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetTrash(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *productCategoriesHandler) RestoreProductCategories(c *fiber.Ctx) error {
+	var (
+		req = new(entity.RestoreProductCategoriesRequest)
+		ctx = c.UserContext()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.Id = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::RestoreProductCategories - Validate request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if err := h.service.RestoreProductCategories(ctx, req); err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(nil, ""))
+}
@@ -11,6 +11,9 @@ type ProductCategoriesRepository interface {
 	DeleteProductCategories(ctx context.Context, req *entity.DeleteProductCategoriesRequest) error
 	UpdateProductCategories(ctx context.Context, req *entity.UpdateProductCategoriesRequest) (*entity.UpdateProductCategoriesResponse, error)
 	GetProductCategoriess(ctx context.Context, req *entity.ProductCategoriesRequest) (*entity.ProductCategoriesResponse, error)
+	GetTrash(ctx context.Context, req *entity.TrashRequest) (*entity.TrashResponse, error)
+	RestoreProductCategories(ctx context.Context, req *entity.RestoreProductCategoriesRequest) error
+	PurgeProductCategories(ctx context.Context, req *entity.PurgeRequest) (int64, error)
 }
 
 type ProductCategoriesService interface {
@@ -19,4 +22,7 @@ type ProductCategoriesService interface {
 	DeleteProductCategories(ctx context.Context, req *entity.DeleteProductCategoriesRequest) error
 	UpdateProductCategories(ctx context.Context, req *entity.UpdateProductCategoriesRequest) (*entity.UpdateProductCategoriesResponse, error)
 	GetProductCategoriess(ctx context.Context, req *entity.ProductCategoriesRequest) (*entity.ProductCategoriesResponse, error)
+	GetTrash(ctx context.Context, req *entity.TrashRequest) (*entity.TrashResponse, error)
+	RestoreProductCategories(ctx context.Context, req *entity.RestoreProductCategoriesRequest) error
+	PurgeProductCategories(ctx context.Context, req *entity.PurgeRequest) (int64, error)
 }
@@ -3,7 +3,12 @@ package repository
 import (
 	"codebase-app/internal/module/product-categories/entity"
 	"codebase-app/internal/module/product-categories/ports"
+	"codebase-app/pkg/database"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/types"
 	"context"
+	"database/sql"
+	"fmt"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/rs/zerolog/log"
@@ -12,12 +17,12 @@ import (
 var _ ports.ProductCategoriesRepository = &productCategoriesRepository{}
 
 type productCategoriesRepository struct {
-	db *sqlx.DB
+	db *database.DB
 }
 
 func NewProductCategoriesRepository(db *sqlx.DB) *productCategoriesRepository {
 	return &productCategoriesRepository{
-		db: db,
+		db: database.New(db),
 	}
 }
 
@@ -33,7 +38,7 @@ func (r *productCategoriesRepository) CreateProductCategories(ctx context.Contex
 		req.Name,
 	).Scan(&resp.Id)
 	if err != nil {
-		log.Error().Err(err).Any("payload", req).Msg("repository::CreateProductCategories - Failed to create ProductCategories")
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::CreateProductCategories - Failed to create ProductCategories")
 		return nil, err
 	}
 
@@ -44,30 +49,129 @@ func (r *productCategoriesRepository) GetProductCategories(ctx context.Context,
 	var resp = new(entity.GetProductCategoriesResponse)
 	// Your code here
 	query := `
-		SELECT name
+		SELECT name, version
 		FROM product_categories
-		WHERE id = ?
+		WHERE id = ? AND deleted_at IS NULL
 	`
 
 	err := r.db.QueryRowxContext(ctx, r.db.Rebind(query), req.Id).StructScan(resp)
 	if err != nil {
-		log.Error().Err(err).Any("payload", req).Msg("repository::GetProductCategories - Failed to get ProductCategories")
+		if err == sql.ErrNoRows {
+			log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("repository::GetProductCategories - Category not found")
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Kategori tidak ditemukan"))
+		}
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::GetProductCategories - Failed to get ProductCategories")
 		return nil, err
 	}
 
 	return resp, nil
 }
 
-func (r *productCategoriesRepository) DeleteProductCategories(ctx context.Context, req *entity.DeleteProductCategoriesRequest) error {
-	query := `
-		UPDATE product_categories
-		SET deleted_at = NOW()
-		WHERE id = ? 
-	`
+// dependentProductsLimit caps the products listed when a category in use
+// cannot be deleted.
+const dependentProductsLimit = 20
+
+func (r *productCategoriesRepository) DeleteProductCategories(ctx context.Context, req *entity.DeleteProductCategoriesRequest) (err error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::DeleteProductCategories - Failed to begin transaction")
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				log.Ctx(ctx).Error().Err(errRollback).Any("payload", req).Msg("repository::DeleteProductCategories - Failed to rollback transaction")
+			}
+		}
+	}()
+
+	var reassignTo any
+	if req.ReassignTo != "" {
+		reassignTo = req.ReassignTo
+	}
+
+	// lock both categories so no product moves into them meanwhile
+	var locked []string
+	err = tx.SelectContext(ctx, &locked, tx.Rebind(`
+		SELECT id
+		FROM product_categories
+		WHERE id IN (?, ?) AND deleted_at IS NULL
+		ORDER BY id
+		FOR UPDATE
+	`), req.Id, reassignTo)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::DeleteProductCategories - Failed to lock categories")
+		return err
+	}
+
+	found := make(map[string]bool, len(locked))
+	for _, id := range locked {
+		found[id] = true
+	}
+
+	if !found[req.Id] {
+		log.Ctx(ctx).Warn().Any("payload", req).Msg("repository::DeleteProductCategories - Category not found")
+		err = errmsg.NewCustomErrors(404, errmsg.WithMessage("Kategori tidak ditemukan"))
+		return err
+	}
+
+	if req.ReassignTo != "" {
+		if !found[req.ReassignTo] {
+			log.Ctx(ctx).Warn().Any("payload", req).Msg("repository::DeleteProductCategories - Reassign target not found")
+			err = errmsg.NewCustomErrors(422, errmsg.WithErrors("reassign_to", "Kategori tujuan tidak ditemukan"))
+			return err
+		}
 
-	_, err := r.db.ExecContext(ctx, r.db.Rebind(query), req.Id)
+		// soft-deleted products move too, so a restore never lands in a deleted category
+		_, err = tx.ExecContext(ctx, tx.Rebind(`
+			UPDATE products
+			SET category_id = ?, updated_at = NOW()
+			WHERE category_id = ?
+		`), req.ReassignTo, req.Id)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::DeleteProductCategories - Failed to reassign products")
+			return err
+		}
+	} else {
+		type dependent struct {
+			TotalData int    `db:"total_data"`
+			Id        string `db:"id"`
+			Name      string `db:"name"`
+		}
+
+		var dependents []dependent
+		err = tx.SelectContext(ctx, &dependents, tx.Rebind(`
+			SELECT COUNT(id) OVER() AS total_data, id, name
+			FROM products
+			WHERE category_id = ? AND deleted_at IS NULL
+			ORDER BY name
+			LIMIT ?
+		`), req.Id, dependentProductsLimit)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::DeleteProductCategories - Failed to get dependent products")
+			return err
+		}
+
+		if len(dependents) > 0 {
+			log.Ctx(ctx).Warn().Any("payload", req).Int("products", dependents[0].TotalData).Msg("repository::DeleteProductCategories - Category in use")
+			errConflict := errmsg.NewCustomErrors(409, errmsg.WithMessage(
+				fmt.Sprintf("Kategori masih digunakan oleh %d produk, pindahkan produk dengan reassign_to", dependents[0].TotalData)))
+			for _, d := range dependents {
+				errConflict.Add("products", d.Id+": "+d.Name)
+			}
+			err = errConflict
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(`UPDATE product_categories SET deleted_at = NOW() WHERE id = ?`), req.Id)
 	if err != nil {
-		log.Error().Err(err).Any("payload", req).Msg("repository::DeleteProductCategories - Failed to delete ProductCategories")
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::DeleteProductCategories - Failed to delete ProductCategories")
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::DeleteProductCategories - Failed to commit transaction")
 		return err
 	}
 
@@ -75,26 +179,61 @@ func (r *productCategoriesRepository) DeleteProductCategories(ctx context.Contex
 }
 
 func (r *productCategoriesRepository) UpdateProductCategories(ctx context.Context, req *entity.UpdateProductCategoriesRequest) (*entity.UpdateProductCategoriesResponse, error) {
-	var resp = new(entity.UpdateProductCategoriesResponse)
+	var (
+		resp = new(entity.UpdateProductCategoriesResponse)
+		set  = new(types.PatchSet)
+	)
+
+	types.AddPatch(set, "name", req.Name)
+
+	if set.Empty() {
+		log.Ctx(ctx).Warn().Any("payload", req).Msg("repository::UpdateProductCategories - Empty patch")
+		return nil, errmsg.NewCustomErrors(400, errmsg.WithMessage("Tidak ada data yang diubah"))
+	}
 
 	query := `
 		UPDATE product_categories
-		SET name = ?, updated_at = NOW()
-		WHERE id = ?
-		RETURNING id
+		SET ` + set.Clause() + `, updated_at = NOW()
+		WHERE id = ? AND deleted_at IS NULL AND version = ?
+		RETURNING id, version
 	`
 
-	err := r.db.QueryRowxContext(ctx, r.db.Rebind(query),
-		req.Name,
-		req.Id).Scan(&resp.Id)
+	args := append(set.Args(), req.Id, req.Version)
+
+	err := r.db.QueryRowxContext(ctx, r.db.Rebind(query), args...).Scan(&resp.Id, &resp.Version)
 	if err != nil {
-		log.Error().Err(err).Any("payload", req).Msg("repository::UpdateProductCategories - Failed to update ProductCategories")
+		if err == sql.ErrNoRows {
+			return nil, r.updateMissError(ctx, req)
+		}
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::UpdateProductCategories - Failed to update ProductCategories")
 		return nil, err
 	}
 
 	return resp, nil
 }
 
+// updateMissError tells a missing category apart from a stale If-Match
+// version after an optimistic update matched no row.
+func (r *productCategoriesRepository) updateMissError(ctx context.Context, req *entity.UpdateProductCategoriesRequest) error {
+	var exists bool
+
+	query := `SELECT EXISTS (SELECT 1 FROM product_categories WHERE id = ? AND deleted_at IS NULL)`
+
+	err := r.db.GetContext(ctx, &exists, r.db.Rebind(query), req.Id)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::updateMissError - Failed to check category")
+		return err
+	}
+
+	if !exists {
+		log.Ctx(ctx).Warn().Any("payload", req).Msg("repository::UpdateProductCategories - Category not found")
+		return errmsg.NewCustomErrors(404, errmsg.WithMessage("Kategori tidak ditemukan"))
+	}
+
+	log.Ctx(ctx).Warn().Any("payload", req).Msg("repository::UpdateProductCategories - Version mismatch")
+	return errmsg.NewCustomErrors(412, errmsg.WithMessage("Kategori telah diubah oleh pengguna lain, muat ulang lalu coba lagi"))
+}
+
 func (r *productCategoriesRepository) GetProductCategoriess(ctx context.Context, req *entity.ProductCategoriesRequest) (*entity.ProductCategoriesResponse, error) {
 	type dao struct {
 		TotalData int `db:"total_data"`
@@ -123,7 +262,7 @@ func (r *productCategoriesRepository) GetProductCategoriess(ctx context.Context,
 		req.Paginate*(req.Page-1),
 	)
 	if err != nil {
-		log.Error().Err(err).Any("payload", req).Msg("repository::GetProductCategories - Failed to get ProductCategories")
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::GetProductCategories - Failed to get ProductCategories")
 		return nil, err
 	}
 
@@ -139,3 +278,90 @@ func (r *productCategoriesRepository) GetProductCategoriess(ctx context.Context,
 
 	return resp, nil
 }
+
+func (r *productCategoriesRepository) GetTrash(ctx context.Context, req *entity.TrashRequest) (*entity.TrashResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.TrashItem
+	}
+
+	var (
+		resp = new(entity.TrashResponse)
+		data = make([]dao, 0, req.Paginate)
+	)
+	resp.Items = make([]entity.TrashItem, 0, req.Paginate)
+
+	query := `
+		SELECT
+			COUNT(id) OVER() as total_data,
+			id,
+			name,
+			deleted_at
+		FROM product_categories
+		WHERE
+			deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+		LIMIT ? OFFSET ?
+	`
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query),
+		req.Paginate,
+		req.Paginate*(req.Page-1),
+	)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::GetTrash - Failed to get deleted categories")
+		return nil, err
+	}
+
+	if len(data) > 0 {
+		resp.Meta.TotalData = data[0].TotalData
+	}
+
+	for _, d := range data {
+		resp.Items = append(resp.Items, d.TrashItem)
+	}
+
+	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
+
+	return resp, nil
+}
+
+func (r *productCategoriesRepository) RestoreProductCategories(ctx context.Context, req *entity.RestoreProductCategoriesRequest) error {
+	query := `
+		UPDATE product_categories
+		SET deleted_at = NULL, updated_at = NOW()
+		WHERE id = ? AND deleted_at IS NOT NULL
+	`
+
+	res, err := r.db.ExecContext(ctx, r.db.Rebind(query), req.Id)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::RestoreProductCategories - Failed to restore category")
+		return err
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		log.Ctx(ctx).Warn().Any("payload", req).Msg("repository::RestoreProductCategories - Deleted category not found")
+		return errmsg.NewCustomErrors(404, errmsg.WithMessage("Kategori tidak ditemukan di tempat sampah"))
+	}
+
+	return nil
+}
+
+func (r *productCategoriesRepository) PurgeProductCategories(ctx context.Context, req *entity.PurgeRequest) (int64, error) {
+	// categories still referenced by a product, even a soft-deleted one, are kept
+	query := `
+		DELETE FROM product_categories c
+		WHERE
+			c.deleted_at IS NOT NULL
+			AND c.deleted_at < ?
+			AND NOT EXISTS (SELECT 1 FROM products p WHERE p.category_id = c.id)
+	`
+
+	res, err := r.db.ExecContext(ctx, r.db.Rebind(query), req.Before)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::PurgeProductCategories - Failed to purge categories")
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
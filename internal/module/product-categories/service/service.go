@@ -37,3 +37,15 @@ func (s *productCategoriesService) UpdateProductCategories(ctx context.Context,
 func (s *productCategoriesService) GetProductCategoriess(ctx context.Context, req *entity.ProductCategoriesRequest) (*entity.ProductCategoriesResponse, error) {
 	return s.repo.GetProductCategoriess(ctx, req)
 }
+
+func (s *productCategoriesService) GetTrash(ctx context.Context, req *entity.TrashRequest) (*entity.TrashResponse, error) {
+	return s.repo.GetTrash(ctx, req)
+}
+
+func (s *productCategoriesService) RestoreProductCategories(ctx context.Context, req *entity.RestoreProductCategoriesRequest) error {
+	return s.repo.RestoreProductCategories(ctx, req)
+}
+
+func (s *productCategoriesService) PurgeProductCategories(ctx context.Context, req *entity.PurgeRequest) (int64, error) {
+	return s.repo.PurgeProductCategories(ctx, req)
+}
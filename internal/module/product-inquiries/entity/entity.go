@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"codebase-app/pkg/types"
+	"time"
+)
+
+type CreateInquiryRequest struct {
+	UserId string `prop:"user_id" validate:"uuid" db:"user_id"`
+
+	ProductId string `params:"id" validate:"uuid" db:"product_id"`
+	Question  string `json:"question" validate:"required,min=3,max=1000" db:"question"`
+}
+
+type CreateInquiryResponse struct {
+	Id string `json:"id" db:"id"`
+}
+
+type AnswerInquiryRequest struct {
+	UserId string `prop:"user_id" validate:"uuid" db:"answered_by"`
+
+	ProductId string `params:"id" validate:"uuid" db:"product_id"`
+	Id        string `params:"question_id" validate:"uuid" db:"id"`
+	Answer    string `json:"answer" validate:"required,min=1,max=2000" db:"answer"`
+}
+
+type ReportInquiryRequest struct {
+	UserId string `prop:"user_id" validate:"uuid" db:"user_id"`
+
+	ProductId string `params:"id" validate:"uuid" db:"product_id"`
+	Id        string `params:"question_id" validate:"uuid" db:"inquiry_id"`
+	Reason    string `json:"reason" validate:"required,max=255" db:"reason"`
+
+	HideThreshold int
+}
+
+type InquiriesRequest struct {
+	ProductId  string `params:"id" validate:"uuid"`
+	Unanswered bool   `query:"unanswered"`
+	Page       int    `query:"page" validate:"required"`
+	Paginate   int    `query:"paginate" validate:"required"`
+}
+
+func (r *InquiriesRequest) SetDefault() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Paginate < 1 {
+		r.Paginate = 10
+	}
+}
+
+type InquiryItem struct {
+	Id         string     `json:"id" db:"id"`
+	UserId     string     `json:"user_id" db:"user_id"`
+	Question   string     `json:"question" db:"question"`
+	Answer     *string    `json:"answer" db:"answer"`
+	AnsweredAt *time.Time `json:"answered_at" db:"answered_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+type InquiriesResponse struct {
+	Items []InquiryItem `json:"items"`
+	Meta  types.Meta    `json:"meta"`
+}
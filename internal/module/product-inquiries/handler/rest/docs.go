@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"codebase-app/internal/module/product-inquiries/entity"
+	"codebase-app/pkg/openapi"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Operations documents the routes of Register.
+var Operations = []openapi.Operation{
+	{
+		Handler:  (*inquiryHandler).GetInquiries,
+		Summary:  "List the questions about a product",
+		Request:  entity.InquiriesRequest{},
+		Response: entity.InquiriesResponse{},
+	},
+	{
+		Handler:  (*inquiryHandler).CreateInquiry,
+		Summary:  "Ask a question about a product",
+		Request:  entity.CreateInquiryRequest{},
+		Response: entity.CreateInquiryResponse{},
+		Status:   fiber.StatusCreated,
+	},
+	{
+		Handler: (*inquiryHandler).AnswerInquiry,
+		Summary: "Answer a question, as the seller",
+		Request: entity.AnswerInquiryRequest{},
+	},
+	{
+		Handler: (*inquiryHandler).ReportInquiry,
+		Summary: "Report a question as abusive",
+		Request: entity.ReportInquiryRequest{},
+	},
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"codebase-app/internal/adapter"
+	"codebase-app/internal/middleware"
+	"codebase-app/internal/module/product-inquiries/entity"
+	"codebase-app/internal/module/product-inquiries/ports"
+	"codebase-app/internal/module/product-inquiries/repository"
+	"codebase-app/internal/module/product-inquiries/service"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+type inquiryHandler struct {
+	service ports.InquiryService
+}
+
+func NewInquiryHandler() *inquiryHandler {
+	var (
+		handler = new(inquiryHandler)
+		repo    = repository.NewInquiryRepository(adapter.Adapters.ShopeefunPostgres)
+		service = service.NewInquiryService(repo)
+	)
+	handler.service = service
+
+	return handler
+}
+
+func (h *inquiryHandler) Register(router fiber.Router) {
+	router.Get("/:id/questions", h.GetInquiries)
+	router.Post("/:id/questions", middleware.UserIdHeader, h.CreateInquiry)
+	router.Put("/:id/questions/:question_id/answer", middleware.UserIdHeader, h.AnswerInquiry)
+	router.Post("/:id/questions/:question_id/reports", middleware.UserIdHeader, h.ReportInquiry)
+}
+
+func (h *inquiryHandler) CreateInquiry(c *fiber.Ctx) error {
+	var (
+		req = new(entity.CreateInquiryRequest)
+		ctx = c.UserContext()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("handler::CreateInquiry - Parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.UserId
+	req.ProductId = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::CreateInquiry - Validate request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.CreateInquiry(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response.Success(resp, ""))
+}
+
+func (h *inquiryHandler) AnswerInquiry(c *fiber.Ctx) error {
+	var (
+		req = new(entity.AnswerInquiryRequest)
+		ctx = c.UserContext()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("handler::AnswerInquiry - Parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.UserId
+	req.ProductId = c.Params("id")
+	req.Id = c.Params("question_id")
+
+	if err := v.Validate(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::AnswerInquiry - Validate request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if err := h.service.AnswerInquiry(ctx, req); err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(nil, ""))
+}
+
+func (h *inquiryHandler) ReportInquiry(c *fiber.Ctx) error {
+	var (
+		req = new(entity.ReportInquiryRequest)
+		ctx = c.UserContext()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("handler::ReportInquiry - Parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.UserId
+	req.ProductId = c.Params("id")
+	req.Id = c.Params("question_id")
+
+	if err := v.Validate(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::ReportInquiry - Validate request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if err := h.service.ReportInquiry(ctx, req); err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(nil, ""))
+}
+
+func (h *inquiryHandler) GetInquiries(c *fiber.Ctx) error {
+	var (
+		req = new(entity.InquiriesRequest)
+		ctx = c.UserContext()
+		v   = adapter.Adapters.Validator
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("handler::GetInquiries - Parse request query")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.ProductId = c.Params("id")
+	req.SetDefault()
+
+	if err := v.Validate(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::GetInquiries - Validate request query")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetInquiries(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"codebase-app/internal/module/product-inquiries/entity"
+	"context"
+)
+
+type InquiryRepository interface {
+	CreateInquiry(ctx context.Context, req *entity.CreateInquiryRequest) (*entity.CreateInquiryResponse, error)
+	AnswerInquiry(ctx context.Context, req *entity.AnswerInquiryRequest) error
+	ReportInquiry(ctx context.Context, req *entity.ReportInquiryRequest) error
+	GetInquiries(ctx context.Context, req *entity.InquiriesRequest) (*entity.InquiriesResponse, error)
+}
+
+type InquiryService interface {
+	CreateInquiry(ctx context.Context, req *entity.CreateInquiryRequest) (*entity.CreateInquiryResponse, error)
+	AnswerInquiry(ctx context.Context, req *entity.AnswerInquiryRequest) error
+	ReportInquiry(ctx context.Context, req *entity.ReportInquiryRequest) error
+	GetInquiries(ctx context.Context, req *entity.InquiriesRequest) (*entity.InquiriesResponse, error)
+}
@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"codebase-app/internal/module/product-inquiries/entity"
+	"codebase-app/internal/module/product-inquiries/ports"
+	"codebase-app/pkg/database"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+var _ ports.InquiryRepository = &inquiryRepository{}
+
+type inquiryRepository struct {
+	db *database.DB
+}
+
+func NewInquiryRepository(db *sqlx.DB) *inquiryRepository {
+	return &inquiryRepository{
+		db: database.New(db),
+	}
+}
+
+func (r *inquiryRepository) CreateInquiry(ctx context.Context, req *entity.CreateInquiryRequest) (*entity.CreateInquiryResponse, error) {
+	var resp = new(entity.CreateInquiryResponse)
+
+	query := `
+		INSERT INTO product_inquiries (product_id, user_id, question)
+		SELECT id, ?, ?
+		FROM products
+		WHERE id = ? AND deleted_at IS NULL AND status = 'active'
+		RETURNING id
+	`
+
+	err := r.db.QueryRowxContext(ctx, r.db.Rebind(query), req.UserId, req.Question, req.ProductId).Scan(&resp.Id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("repository::CreateInquiry - Product not found")
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Produk tidak ditemukan"))
+		}
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::CreateInquiry - Failed to create inquiry")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *inquiryRepository) AnswerInquiry(ctx context.Context, req *entity.AnswerInquiryRequest) error {
+	// only the owner of the shop selling the product may answer
+	query := `
+		UPDATE product_inquiries pi
+		SET answer = ?, answered_by = ?, answered_at = NOW(), updated_at = NOW()
+		FROM products p
+		JOIN shops s ON s.id = p.shop_id
+		WHERE
+			pi.id = ?
+			AND pi.product_id = ?
+			AND pi.is_hidden = FALSE
+			AND p.id = pi.product_id
+			AND p.deleted_at IS NULL
+			AND s.user_id = ?
+	`
+
+	res, err := r.db.ExecContext(ctx, r.db.Rebind(query), req.Answer, req.UserId, req.Id, req.ProductId, req.UserId)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::AnswerInquiry - Failed to answer inquiry")
+		return err
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		log.Ctx(ctx).Warn().Any("payload", req).Msg("repository::AnswerInquiry - Inquiry not found")
+		return errmsg.NewCustomErrors(404, errmsg.WithMessage("Pertanyaan tidak ditemukan"))
+	}
+
+	return nil
+}
+
+func (r *inquiryRepository) ReportInquiry(ctx context.Context, req *entity.ReportInquiryRequest) (err error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::ReportInquiry - Failed to begin transaction")
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				log.Ctx(ctx).Error().Err(errRollback).Any("payload", req).Msg("repository::ReportInquiry - Failed to rollback transaction")
+			}
+		}
+	}()
+
+	query := `
+		INSERT INTO product_inquiry_reports (inquiry_id, user_id, reason)
+		SELECT id, ?, ?
+		FROM product_inquiries
+		WHERE id = ? AND product_id = ?
+		ON CONFLICT (inquiry_id, user_id) DO NOTHING
+	`
+
+	res, err := tx.ExecContext(ctx, tx.Rebind(query), req.UserId, req.Reason, req.Id, req.ProductId)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::ReportInquiry - Failed to report inquiry")
+		return err
+	}
+
+	// reporting twice is a no-op, the count only grows once per user
+	if affected, _ := res.RowsAffected(); affected > 0 {
+		query = `
+			UPDATE product_inquiries
+			SET
+				report_count = report_count + 1,
+				is_hidden = is_hidden OR report_count + 1 >= ?,
+				updated_at = NOW()
+			WHERE id = ?
+		`
+
+		_, err = tx.ExecContext(ctx, tx.Rebind(query), req.HideThreshold, req.Id)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::ReportInquiry - Failed to increment report count")
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::ReportInquiry - Failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
+
+func (r *inquiryRepository) GetInquiries(ctx context.Context, req *entity.InquiriesRequest) (*entity.InquiriesResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.InquiryItem
+	}
+
+	var (
+		resp = new(entity.InquiriesResponse)
+		data = make([]dao, 0, req.Paginate)
+	)
+	resp.Items = make([]entity.InquiryItem, 0, req.Paginate)
+
+	query := `
+		SELECT
+			COUNT(id) OVER() as total_data,
+			id,
+			user_id,
+			question,
+			answer,
+			answered_at,
+			created_at
+		FROM product_inquiries
+		WHERE
+			product_id = ?
+			AND is_hidden = FALSE
+			AND EXISTS (SELECT 1 FROM products p WHERE p.id = product_inquiries.product_id AND p.deleted_at IS NULL)
+	`
+
+	if req.Unanswered {
+		query += " AND answer IS NULL"
+	}
+
+	query += " ORDER BY created_at DESC LIMIT ? OFFSET ?"
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query),
+		req.ProductId,
+		req.Paginate,
+		req.Paginate*(req.Page-1),
+	)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::GetInquiries - Failed to get inquiries")
+		return nil, err
+	}
+
+	if len(data) > 0 {
+		resp.Meta.TotalData = data[0].TotalData
+	}
+
+	for _, d := range data {
+		resp.Items = append(resp.Items, d.InquiryItem)
+	}
+
+	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
+
+	return resp, nil
+}
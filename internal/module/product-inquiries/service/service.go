@@ -0,0 +1,37 @@
+package service
+
+import (
+	"codebase-app/internal/infrastructure/config"
+	"codebase-app/internal/module/product-inquiries/entity"
+	"codebase-app/internal/module/product-inquiries/ports"
+	"context"
+)
+
+var _ ports.InquiryService = &inquiryService{}
+
+type inquiryService struct {
+	repo ports.InquiryRepository
+}
+
+func NewInquiryService(repo ports.InquiryRepository) *inquiryService {
+	return &inquiryService{
+		repo: repo,
+	}
+}
+
+func (s *inquiryService) CreateInquiry(ctx context.Context, req *entity.CreateInquiryRequest) (*entity.CreateInquiryResponse, error) {
+	return s.repo.CreateInquiry(ctx, req)
+}
+
+func (s *inquiryService) AnswerInquiry(ctx context.Context, req *entity.AnswerInquiryRequest) error {
+	return s.repo.AnswerInquiry(ctx, req)
+}
+
+func (s *inquiryService) ReportInquiry(ctx context.Context, req *entity.ReportInquiryRequest) error {
+	req.HideThreshold = config.Envs.Moderation.ReportHideThreshold
+	return s.repo.ReportInquiry(ctx, req)
+}
+
+func (s *inquiryService) GetInquiries(ctx context.Context, req *entity.InquiriesRequest) (*entity.InquiriesResponse, error) {
+	return s.repo.GetInquiries(ctx, req)
+}
@@ -0,0 +1,93 @@
+package entity
+
+import (
+	"codebase-app/pkg/types"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type CreateReviewRequest struct {
+	UserId string `prop:"user_id" validate:"uuid" db:"user_id"`
+
+	ProductId string   `params:"id" validate:"uuid" db:"product_id"`
+	Rating    int      `json:"rating" validate:"required,min=1,max=5" db:"rating"`
+	Body      *string  `json:"body" validate:"omitempty,max=2000" db:"body"`
+	Images    []string `json:"images" validate:"omitempty,max=5,dive,url" db:"images"`
+}
+
+type CreateReviewResponse struct {
+	Id string `json:"id" db:"id"`
+}
+
+type UpdateReviewRequest struct {
+	UserId string `prop:"user_id" validate:"uuid" db:"user_id"`
+
+	ProductId string   `params:"id" validate:"uuid" db:"product_id"`
+	Id        string   `params:"review_id" validate:"uuid" db:"id"`
+	Rating    int      `json:"rating" validate:"required,min=1,max=5" db:"rating"`
+	Body      *string  `json:"body" validate:"omitempty,max=2000" db:"body"`
+	Images    []string `json:"images" validate:"omitempty,max=5,dive,url" db:"images"`
+}
+
+type DeleteReviewRequest struct {
+	UserId string `prop:"user_id" validate:"uuid" db:"user_id"`
+
+	ProductId string `params:"id" validate:"uuid" db:"product_id"`
+	Id        string `params:"review_id" validate:"uuid" db:"id"`
+}
+
+type ReplyReviewRequest struct {
+	UserId string `prop:"user_id" validate:"uuid" db:"user_id"`
+
+	ProductId string `params:"id" validate:"uuid" db:"product_id"`
+	Id        string `params:"review_id" validate:"uuid" db:"id"`
+	Reply     string `json:"reply" validate:"required,max=2000" db:"seller_reply"`
+}
+
+type FlagReviewRequest struct {
+	UserId string `prop:"user_id" validate:"uuid" db:"user_id"`
+
+	ProductId string `params:"id" validate:"uuid" db:"product_id"`
+	Id        string `params:"review_id" validate:"uuid" db:"review_id"`
+	Reason    string `json:"reason" validate:"required,max=255" db:"reason"`
+}
+
+type ModerateReviewRequest struct {
+	Id     string `params:"review_id" validate:"uuid" db:"id"`
+	Hidden *bool  `json:"hidden" validate:"required" db:"is_hidden"`
+}
+
+type ReviewsRequest struct {
+	ProductId string `params:"id" validate:"uuid"`
+	Rating    int    `query:"rating" validate:"omitempty,min=1,max=5"`
+	Page      int    `query:"page" validate:"required"`
+	Paginate  int    `query:"paginate" validate:"required"`
+}
+
+func (r *ReviewsRequest) SetDefault() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Paginate < 1 {
+		r.Paginate = 10
+	}
+}
+
+type ReviewItem struct {
+	Id              string         `json:"id" db:"id"`
+	UserId          string         `json:"user_id" db:"user_id"`
+	Rating          int            `json:"rating" db:"rating"`
+	Body            *string        `json:"body" db:"body"`
+	Images          pq.StringArray `json:"images" db:"images"`
+	SellerReply     *string        `json:"seller_reply" db:"seller_reply"`
+	SellerRepliedAt *time.Time     `json:"seller_replied_at" db:"seller_replied_at"`
+	CreatedAt       time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+type ReviewsResponse struct {
+	Items []ReviewItem `json:"items"`
+	Meta  types.Meta   `json:"meta"`
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"codebase-app/internal/module/product-reviews/entity"
+	"codebase-app/pkg/openapi"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Operations documents the routes of Register.
+var Operations = []openapi.Operation{
+	{
+		Handler: (*reviewHandler).ModerateReview,
+		Summary: "Hide or show a review, as an admin",
+		Request: entity.ModerateReviewRequest{},
+	},
+	{
+		Handler:  (*reviewHandler).GetReviews,
+		Summary:  "List the reviews of a product",
+		Request:  entity.ReviewsRequest{},
+		Response: entity.ReviewsResponse{},
+	},
+	{
+		Handler:  (*reviewHandler).CreateReview,
+		Summary:  "Review a product",
+		Request:  entity.CreateReviewRequest{},
+		Response: entity.CreateReviewResponse{},
+		Status:   fiber.StatusCreated,
+	},
+	{
+		Handler: (*reviewHandler).UpdateReview,
+		Summary: "Update your review",
+		Request: entity.UpdateReviewRequest{},
+	},
+	{
+		Handler: (*reviewHandler).DeleteReview,
+		Summary: "Delete your review",
+		Request: entity.DeleteReviewRequest{},
+	},
+	{
+		Handler: (*reviewHandler).ReplyReview,
+		Summary: "Reply to a review, as the seller",
+		Request: entity.ReplyReviewRequest{},
+	},
+	{
+		Handler: (*reviewHandler).FlagReview,
+		Summary: "Report a review as abusive",
+		Request: entity.FlagReviewRequest{},
+	},
+}
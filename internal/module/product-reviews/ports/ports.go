@@ -0,0 +1,26 @@
+package ports
+
+import (
+	"codebase-app/internal/module/product-reviews/entity"
+	"context"
+)
+
+type ReviewRepository interface {
+	CreateReview(ctx context.Context, req *entity.CreateReviewRequest) (*entity.CreateReviewResponse, error)
+	UpdateReview(ctx context.Context, req *entity.UpdateReviewRequest) error
+	DeleteReview(ctx context.Context, req *entity.DeleteReviewRequest) error
+	ReplyReview(ctx context.Context, req *entity.ReplyReviewRequest) error
+	FlagReview(ctx context.Context, req *entity.FlagReviewRequest) error
+	ModerateReview(ctx context.Context, req *entity.ModerateReviewRequest) error
+	GetReviews(ctx context.Context, req *entity.ReviewsRequest) (*entity.ReviewsResponse, error)
+}
+
+type ReviewService interface {
+	CreateReview(ctx context.Context, req *entity.CreateReviewRequest) (*entity.CreateReviewResponse, error)
+	UpdateReview(ctx context.Context, req *entity.UpdateReviewRequest) error
+	DeleteReview(ctx context.Context, req *entity.DeleteReviewRequest) error
+	ReplyReview(ctx context.Context, req *entity.ReplyReviewRequest) error
+	FlagReview(ctx context.Context, req *entity.FlagReviewRequest) error
+	ModerateReview(ctx context.Context, req *entity.ModerateReviewRequest) error
+	GetReviews(ctx context.Context, req *entity.ReviewsRequest) (*entity.ReviewsResponse, error)
+}
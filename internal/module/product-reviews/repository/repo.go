@@ -0,0 +1,371 @@
+package repository
+
+import (
+	"codebase-app/internal/module/product-reviews/entity"
+	"codebase-app/internal/module/product-reviews/ports"
+	"codebase-app/pkg/database"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+var _ ports.ReviewRepository = &reviewRepository{}
+
+type reviewRepository struct {
+	db *database.DB
+}
+
+func NewReviewRepository(db *sqlx.DB) *reviewRepository {
+	return &reviewRepository{
+		db: database.New(db),
+	}
+}
+
+// withTx runs fn inside a transaction that is committed when fn returns nil.
+func (r *reviewRepository) withTx(ctx context.Context, fn func(tx *database.Tx) error) (err error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("repository::withTx - Failed to begin transaction")
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				log.Ctx(ctx).Error().Err(errRollback).Msg("repository::withTx - Failed to rollback transaction")
+			}
+		}
+	}()
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("repository::withTx - Failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
+
+// lockProduct serializes review writes of a product so the rating summary
+// is always recomputed from a consistent set of reviews.
+func (r *reviewRepository) lockProduct(ctx context.Context, tx *database.Tx, productId string) error {
+	var id string
+
+	query := `
+		SELECT id
+		FROM products
+		WHERE id = ? AND deleted_at IS NULL
+		FOR UPDATE
+	`
+
+	err := tx.QueryRowxContext(ctx, tx.Rebind(query), productId).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Ctx(ctx).Warn().Err(err).Str("product_id", productId).Msg("repository::lockProduct - Product not found")
+			return errmsg.NewCustomErrors(404, errmsg.WithMessage("Produk tidak ditemukan"))
+		}
+		log.Ctx(ctx).Error().Err(err).Str("product_id", productId).Msg("repository::lockProduct - Failed to lock product")
+		return err
+	}
+
+	return nil
+}
+
+// refreshSummary recomputes the rating summary of a product from its visible reviews.
+func (r *reviewRepository) refreshSummary(ctx context.Context, tx *database.Tx, productId string) error {
+	query := `
+		INSERT INTO product_rating_summaries (
+			product_id, rating_count, rating_sum, rating_average,
+			star_1, star_2, star_3, star_4, star_5, updated_at
+		)
+		SELECT
+			?,
+			COUNT(*),
+			COALESCE(SUM(rating), 0),
+			COALESCE(ROUND(AVG(rating), 2), 0),
+			COUNT(*) FILTER (WHERE rating = 1),
+			COUNT(*) FILTER (WHERE rating = 2),
+			COUNT(*) FILTER (WHERE rating = 3),
+			COUNT(*) FILTER (WHERE rating = 4),
+			COUNT(*) FILTER (WHERE rating = 5),
+			NOW()
+		FROM product_reviews
+		WHERE product_id = ? AND is_hidden = FALSE
+		ON CONFLICT (product_id) DO UPDATE SET
+			rating_count = EXCLUDED.rating_count,
+			rating_sum = EXCLUDED.rating_sum,
+			rating_average = EXCLUDED.rating_average,
+			star_1 = EXCLUDED.star_1,
+			star_2 = EXCLUDED.star_2,
+			star_3 = EXCLUDED.star_3,
+			star_4 = EXCLUDED.star_4,
+			star_5 = EXCLUDED.star_5,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := tx.ExecContext(ctx, tx.Rebind(query), productId, productId)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("product_id", productId).Msg("repository::refreshSummary - Failed to refresh rating summary")
+		return err
+	}
+
+	return nil
+}
+
+func (r *reviewRepository) CreateReview(ctx context.Context, req *entity.CreateReviewRequest) (*entity.CreateReviewResponse, error) {
+	var resp = new(entity.CreateReviewResponse)
+
+	if req.Images == nil {
+		req.Images = []string{}
+	}
+
+	err := r.withTx(ctx, func(tx *database.Tx) error {
+		if err := r.lockProduct(ctx, tx, req.ProductId); err != nil {
+			return err
+		}
+
+		query := `
+			INSERT INTO product_reviews (product_id, user_id, rating, body, images)
+			VALUES (?, ?, ?, ?, ?)
+			RETURNING id
+		`
+
+		err := tx.QueryRowxContext(ctx, tx.Rebind(query),
+			req.ProductId,
+			req.UserId,
+			req.Rating,
+			req.Body,
+			pq.Array(req.Images)).Scan(&resp.Id)
+		if err != nil {
+			if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
+				log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("repository::CreateReview - Review already exists")
+				return errmsg.NewCustomErrors(409, errmsg.WithMessage("Anda sudah memberikan ulasan untuk produk ini"))
+			}
+			log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::CreateReview - Failed to create review")
+			return err
+		}
+
+		return r.refreshSummary(ctx, tx, req.ProductId)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *reviewRepository) UpdateReview(ctx context.Context, req *entity.UpdateReviewRequest) error {
+	if req.Images == nil {
+		req.Images = []string{}
+	}
+
+	return r.withTx(ctx, func(tx *database.Tx) error {
+		if err := r.lockProduct(ctx, tx, req.ProductId); err != nil {
+			return err
+		}
+
+		query := `
+			UPDATE product_reviews
+			SET rating = ?, body = ?, images = ?, updated_at = NOW()
+			WHERE id = ? AND product_id = ? AND user_id = ?
+		`
+
+		res, err := tx.ExecContext(ctx, tx.Rebind(query),
+			req.Rating,
+			req.Body,
+			pq.Array(req.Images),
+			req.Id,
+			req.ProductId,
+			req.UserId)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::UpdateReview - Failed to update review")
+			return err
+		}
+
+		if affected, _ := res.RowsAffected(); affected == 0 {
+			log.Ctx(ctx).Warn().Any("payload", req).Msg("repository::UpdateReview - Review not found")
+			return errmsg.NewCustomErrors(404, errmsg.WithMessage("Ulasan tidak ditemukan"))
+		}
+
+		return r.refreshSummary(ctx, tx, req.ProductId)
+	})
+}
+
+func (r *reviewRepository) DeleteReview(ctx context.Context, req *entity.DeleteReviewRequest) error {
+	return r.withTx(ctx, func(tx *database.Tx) error {
+		if err := r.lockProduct(ctx, tx, req.ProductId); err != nil {
+			return err
+		}
+
+		query := `
+			DELETE FROM product_reviews
+			WHERE id = ? AND product_id = ? AND user_id = ?
+		`
+
+		res, err := tx.ExecContext(ctx, tx.Rebind(query), req.Id, req.ProductId, req.UserId)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::DeleteReview - Failed to delete review")
+			return err
+		}
+
+		if affected, _ := res.RowsAffected(); affected == 0 {
+			log.Ctx(ctx).Warn().Any("payload", req).Msg("repository::DeleteReview - Review not found")
+			return errmsg.NewCustomErrors(404, errmsg.WithMessage("Ulasan tidak ditemukan"))
+		}
+
+		return r.refreshSummary(ctx, tx, req.ProductId)
+	})
+}
+
+func (r *reviewRepository) ReplyReview(ctx context.Context, req *entity.ReplyReviewRequest) error {
+	// only the owner of the shop selling the product may reply
+	query := `
+		UPDATE product_reviews pr
+		SET seller_reply = ?, seller_replied_at = NOW(), updated_at = NOW()
+		FROM products p
+		JOIN shops s ON s.id = p.shop_id
+		WHERE
+			pr.id = ?
+			AND pr.product_id = ?
+			AND p.id = pr.product_id
+			AND p.deleted_at IS NULL
+			AND s.user_id = ?
+	`
+
+	res, err := r.db.ExecContext(ctx, r.db.Rebind(query), req.Reply, req.Id, req.ProductId, req.UserId)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::ReplyReview - Failed to reply review")
+		return err
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		log.Ctx(ctx).Warn().Any("payload", req).Msg("repository::ReplyReview - Review not found")
+		return errmsg.NewCustomErrors(404, errmsg.WithMessage("Ulasan tidak ditemukan"))
+	}
+
+	return nil
+}
+
+func (r *reviewRepository) FlagReview(ctx context.Context, req *entity.FlagReviewRequest) error {
+	return r.withTx(ctx, func(tx *database.Tx) error {
+		query := `
+			INSERT INTO product_review_flags (review_id, user_id, reason)
+			SELECT id, ?, ?
+			FROM product_reviews
+			WHERE id = ? AND product_id = ?
+			ON CONFLICT (review_id, user_id) DO NOTHING
+		`
+
+		res, err := tx.ExecContext(ctx, tx.Rebind(query), req.UserId, req.Reason, req.Id, req.ProductId)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::FlagReview - Failed to flag review")
+			return err
+		}
+
+		// flagging twice is a no-op
+		if affected, _ := res.RowsAffected(); affected == 0 {
+			return nil
+		}
+
+		_, err = tx.ExecContext(ctx, tx.Rebind(`UPDATE product_reviews SET flag_count = flag_count + 1 WHERE id = ?`), req.Id)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::FlagReview - Failed to increment flag count")
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (r *reviewRepository) ModerateReview(ctx context.Context, req *entity.ModerateReviewRequest) error {
+	return r.withTx(ctx, func(tx *database.Tx) error {
+		var productId string
+
+		err := tx.QueryRowxContext(ctx, tx.Rebind(`SELECT product_id FROM product_reviews WHERE id = ?`), req.Id).Scan(&productId)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("repository::ModerateReview - Review not found")
+				return errmsg.NewCustomErrors(404, errmsg.WithMessage("Ulasan tidak ditemukan"))
+			}
+			log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::ModerateReview - Failed to get review")
+			return err
+		}
+
+		if err := r.lockProduct(ctx, tx, productId); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, tx.Rebind(`UPDATE product_reviews SET is_hidden = ?, updated_at = NOW() WHERE id = ?`), *req.Hidden, req.Id)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::ModerateReview - Failed to moderate review")
+			return err
+		}
+
+		return r.refreshSummary(ctx, tx, productId)
+	})
+}
+
+func (r *reviewRepository) GetReviews(ctx context.Context, req *entity.ReviewsRequest) (*entity.ReviewsResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.ReviewItem
+	}
+
+	var (
+		resp = new(entity.ReviewsResponse)
+		data = make([]dao, 0, req.Paginate)
+		args = []any{req.ProductId}
+	)
+	resp.Items = make([]entity.ReviewItem, 0, req.Paginate)
+
+	query := `
+		SELECT
+			COUNT(id) OVER() as total_data,
+			id,
+			user_id,
+			rating,
+			body,
+			images,
+			seller_reply,
+			seller_replied_at,
+			created_at,
+			updated_at
+		FROM product_reviews
+		WHERE
+			product_id = ?
+			AND is_hidden = FALSE
+			AND EXISTS (SELECT 1 FROM products p WHERE p.id = product_reviews.product_id AND p.deleted_at IS NULL)
+	`
+
+	if req.Rating != 0 {
+		query += " AND rating = ?"
+		args = append(args, req.Rating)
+	}
+
+	query += " ORDER BY created_at DESC LIMIT ? OFFSET ?"
+	args = append(args, req.Paginate, req.Paginate*(req.Page-1))
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query), args...)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::GetReviews - Failed to get reviews")
+		return nil, err
+	}
+
+	if len(data) > 0 {
+		resp.Meta.TotalData = data[0].TotalData
+	}
+
+	for _, d := range data {
+		resp.Items = append(resp.Items, d.ReviewItem)
+	}
+
+	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
+
+	return resp, nil
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"codebase-app/internal/infrastructure/metrics"
+	"codebase-app/internal/module/product-reviews/entity"
+	"codebase-app/internal/module/product-reviews/ports"
+	"context"
+)
+
+var _ ports.ReviewService = &reviewService{}
+
+type reviewService struct {
+	repo ports.ReviewRepository
+}
+
+func NewReviewService(repo ports.ReviewRepository) *reviewService {
+	return &reviewService{
+		repo: repo,
+	}
+}
+
+func (s *reviewService) CreateReview(ctx context.Context, req *entity.CreateReviewRequest) (*entity.CreateReviewResponse, error) {
+	resp, err := s.repo.CreateReview(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics.ReviewsCreated.Inc()
+	return resp, nil
+}
+
+func (s *reviewService) UpdateReview(ctx context.Context, req *entity.UpdateReviewRequest) error {
+	return s.repo.UpdateReview(ctx, req)
+}
+
+func (s *reviewService) DeleteReview(ctx context.Context, req *entity.DeleteReviewRequest) error {
+	return s.repo.DeleteReview(ctx, req)
+}
+
+func (s *reviewService) ReplyReview(ctx context.Context, req *entity.ReplyReviewRequest) error {
+	return s.repo.ReplyReview(ctx, req)
+}
+
+func (s *reviewService) FlagReview(ctx context.Context, req *entity.FlagReviewRequest) error {
+	return s.repo.FlagReview(ctx, req)
+}
+
+func (s *reviewService) ModerateReview(ctx context.Context, req *entity.ModerateReviewRequest) error {
+	return s.repo.ModerateReview(ctx, req)
+}
+
+func (s *reviewService) GetReviews(ctx context.Context, req *entity.ReviewsRequest) (*entity.ReviewsResponse, error) {
+	return s.repo.GetReviews(ctx, req)
+}
@@ -1,103 +1,394 @@
 package entity
 
 import (
+	"codebase-app/pkg/types"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
+type ProductStatus string
+
+const (
+	ProductDraft         ProductStatus = "draft"
+	ProductPendingReview ProductStatus = "pending_review"
+	ProductActive        ProductStatus = "active"
+	ProductArchived      ProductStatus = "archived"
+)
+
+// productTransitions are the moves a seller may make. A product pending
+// review only becomes active through ReviewProduct.
+var productTransitions = map[ProductStatus][]ProductStatus{
+	ProductDraft:         {ProductDraft, ProductPendingReview, ProductActive, ProductArchived},
+	ProductPendingReview: {ProductDraft, ProductArchived},
+	ProductActive:        {ProductActive, ProductArchived},
+	ProductArchived:      {ProductDraft, ProductActive},
+}
+
+// CanTransitionTo reports whether a product may move to next. Drafts and
+// active products may "transition" to themselves to reschedule them.
+func (s ProductStatus) CanTransitionTo(next ProductStatus) bool {
+	for _, allowed := range productTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+
+	return false
+}
+
 type CreateProductRequest struct {
 	UserId string `query:"user_id" validate:"required,uuid"`
 
-	ShopId      string  `json:"shop_id" validate:"required,uuid" db:"shop_id"`
-	CategoryId  string  `json:"category_id" validate:"required,uuid" db:"category_id"`
-	Name        string  `json:"name" validate:"required,max=255,min=3" db:"name"`
-	Description *string `json:"description" validate:"omitempty,max=255,min=3" db:"description"`
-	ImageUrl    *string `json:"image_url" validate:"omitempty,url" db:"image_url"`
-	Brand       *string `json:"brand" validate:"omitempty,max=255,min=3" db:"brand"`
-	Price       float64 `json:"price" validate:"required,numeric" db:"price"`
-	Stock       int64   `json:"stock" validate:"required,numeric" db:"stock"`
+	ShopId      string          `json:"shop_id" validate:"required,uuid" db:"shop_id"`
+	CategoryId  string          `json:"category_id" validate:"required,uuid" db:"category_id"`
+	Name        string          `json:"name" validate:"required,max=255,min=3" db:"name"`
+	Description *string         `json:"description" validate:"omitempty,max=255,min=3" db:"description"`
+	ImageUrl    *string         `json:"image_url" validate:"omitempty,url" db:"image_url"`
+	Brand       *string         `json:"brand" validate:"omitempty,max=255,min=3" db:"brand"`
+	Price       decimal.Decimal `json:"price" validate:"required,gt=0" db:"price"`
+	Currency    string          `json:"currency" validate:"omitempty,iso4217" db:"currency"`
+	Stock       int64           `json:"stock" validate:"required,numeric" db:"stock"`
+
+	Status      ProductStatus `json:"status" validate:"omitempty,oneof=draft pending_review active" db:"status"`
+	PublishAt   *time.Time    `json:"publish_at" db:"publish_at"`
+	UnpublishAt *time.Time    `json:"unpublish_at" db:"unpublish_at"`
+}
+
+func (r *CreateProductRequest) SetDefault() {
+	if r.Status == "" {
+		r.Status = ProductDraft
+	}
+
+	if r.Currency == "" {
+		r.Currency = types.DefaultCurrency
+	}
+	// iso4217 only accepts upper case codes
+	r.Currency = strings.ToUpper(r.Currency)
+}
+
+func (r *CreateProductRequest) CostumValidation() (int, map[string][]string) {
+	return validateSchedule(r.Status, r.PublishAt, r.UnpublishAt)
 }
 
 type CreateProductResponse struct {
-	Id          string    `json:"id" db:"id"`
-	ShopId      string    `json:"shop_id" db:"shop_id"`
-	CategoryId  string    `json:"category_id" db:"category_id"`
-	Name        string    `json:"name" db:"name"`
-	Description *string   `json:"description" db:"description"`
-	ImageUrl    *string   `json:"image_url" db:"image_url"`
-	Brand       *string   `json:"brand" validate:"omitempty,max=255,min=3" db:"brand"`
-	Price       float64   `json:"price" db:"price"`
-	Stock       int       `json:"stock" db:"stock"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	Id          string          `json:"id" db:"id"`
+	ShopId      string          `json:"shop_id" db:"shop_id"`
+	CategoryId  string          `json:"category_id" db:"category_id"`
+	Name        string          `json:"name" db:"name"`
+	Description *string         `json:"description" db:"description"`
+	ImageUrl    *string         `json:"image_url" db:"image_url"`
+	Brand       *string         `json:"brand" validate:"omitempty,max=255,min=3" db:"brand"`
+	Price       decimal.Decimal `json:"price" db:"price"`
+	Currency    string          `json:"currency" db:"currency"`
+	Stock       int             `json:"stock" db:"stock"`
+	Status      ProductStatus   `json:"status" db:"status"`
+	PublishAt   *time.Time      `json:"publish_at" db:"publish_at"`
+	UnpublishAt *time.Time      `json:"unpublish_at" db:"unpublish_at"`
+	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at" db:"updated_at"`
 }
 
 type GetProductRequest struct {
+	// UserId is optional, owners can see their products in any status.
+	UserId string `validate:"omitempty,uuid"`
+
 	Id string `validate:"uuid" db:"id"`
 }
 
 type GetProductResponse struct {
-	Id          string    `json:"id" db:"id"`
-	ShopId      string    `json:"shop_id" db:"shop_id"`
-	ShopName    string    `json:"shop_name" db:"shop_name"`
-	Category    string    `json:"category" db:"category"`
-	CategoryId  string    `json:"category_id" db:"category_id"`
-	Name        string    `json:"name" db:"name"`
-	Description *string   `json:"description" db:"description"`
-	ImageUrl    *string   `json:"image_url" db:"image_url"`
-	Price       float64   `json:"price" db:"price"`
-	Stock       int       `json:"stock" db:"stock"`
-	Brand       *string   `json:"brand" validate:"omitempty,max=255,min=3" db:"brand"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	Id          string  `json:"id" db:"id"`
+	ShopId      string  `json:"shop_id" db:"shop_id"`
+	ShopName    string  `json:"shop_name" db:"shop_name"`
+	Category    string  `json:"category" db:"category"`
+	CategoryId  string  `json:"category_id" db:"category_id"`
+	Name        string  `json:"name" db:"name"`
+	Description *string `json:"description" db:"description"`
+	ImageUrl    *string `json:"image_url" db:"image_url"`
+	// Price is the effective price, OriginalPrice the regular one. They
+	// only differ while a sale is running, until SaleEndsAt.
+	Price         types.Money   `json:"price" db:"price"`
+	OriginalPrice types.Money   `json:"original_price" db:"original_price"`
+	SaleEndsAt    *time.Time    `json:"sale_ends_at" db:"sale_ends_at"`
+	Stock         int           `json:"stock" db:"stock"`
+	Brand         *string       `json:"brand" validate:"omitempty,max=255,min=3" db:"brand"`
+	Status        ProductStatus `json:"status" db:"status"`
+	PublishAt     *time.Time    `json:"publish_at" db:"publish_at"`
+	UnpublishAt   *time.Time    `json:"unpublish_at" db:"unpublish_at"`
+	Rating        Rating        `json:"rating"`
+	Version       int           `json:"-" db:"version"`
+	CreatedAt     time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at" db:"updated_at"`
+}
+
+type Rating struct {
+	Average float64 `json:"average" db:"rating_average"`
+	Count   int     `json:"count" db:"rating_count"`
+	// Histogram is the number of reviews per star, keyed "1" to "5".
+	Histogram map[string]int `json:"histogram"`
 }
 
 type DeleteProductRequest struct {
+	UserId string `prop:"user_id" validate:"uuid"`
+
 	Id string `validate:"uuid" db:"id"`
 }
 
+type RestoreProductRequest struct {
+	UserId string `prop:"user_id" validate:"uuid"`
+
+	Id string `params:"id" validate:"uuid" db:"id"`
+}
+
+type TrashRequest struct {
+	UserId   string `prop:"user_id" validate:"uuid"`
+	Page     int    `query:"page" validate:"required"`
+	Paginate int    `query:"paginate" validate:"required"`
+}
+
+func (r *TrashRequest) SetDefault() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Paginate < 1 {
+		r.Paginate = 10
+	}
+}
+
+type TrashItem struct {
+	Id         string    `json:"id" db:"id"`
+	ShopId     string    `json:"shop_id" db:"shop_id"`
+	CategoryId string    `json:"category_id" db:"category_id"`
+	Name       string    `json:"name" db:"name"`
+	ImageUrl   *string   `json:"image_url" db:"image_url"`
+	DeletedAt  time.Time `json:"deleted_at" db:"deleted_at"`
+}
+
+type TrashResponse struct {
+	Items []TrashItem `json:"items"`
+	Meta  types.Meta  `json:"meta"`
+}
+
+// PurgeRequest hard-deletes the products soft-deleted before Before.
+type PurgeRequest struct {
+	Before time.Time
+}
+
+// UpdateProductRequest is a JSON Merge Patch (RFC 7396): only the members
+// present in the body are changed and null clears a nullable column.
 type UpdateProductRequest struct {
 	UserId string `query:"user_id" validate:"required,uuid"`
 
-	Id          string  `params:"id" validate:"required,uuid" db:"id"`
-	CategoryId  string  `json:"category_id" validate:"required,uuid" db:"category_id"`
-	Name        string  `json:"name" validate:"required,max=255,min=3" db:"name"`
-	Description *string `json:"description" validate:"omitempty,max=255,min=3" db:"description"`
-	ImageUrl    *string `json:"image_url" validate:"omitempty,url" db:"image_url"`
-	Brand       *string `json:"brand" validate:"omitempty,max=255,min=3" db:"brand"`
-	Price       float64 `json:"price" validate:"required,numeric" db:"price"`
-	Stock       int64   `json:"stock" validate:"required,numeric" db:"stock"`
+	Id          string                       `params:"id" validate:"required,uuid" db:"id"`
+	CategoryId  types.Patch[string]          `json:"category_id" validate:"omitempty,uuid" db:"category_id"`
+	Name        types.Patch[string]          `json:"name" validate:"omitempty,max=255,min=3" db:"name"`
+	Description types.Patch[string]          `json:"description" validate:"omitempty,max=255,min=3" db:"description"`
+	ImageUrl    types.Patch[string]          `json:"image_url" validate:"omitempty,url" db:"image_url"`
+	Brand       types.Patch[string]          `json:"brand" validate:"omitempty,max=255,min=3" db:"brand"`
+	Price       types.Patch[decimal.Decimal] `json:"price" validate:"omitempty,gt=0" db:"price"`
+	Currency    types.Patch[string]          `json:"currency" validate:"omitempty,iso4217" db:"currency"`
+	Stock       types.Patch[int64]           `json:"stock" validate:"omitempty,gte=0" db:"stock"`
+
+	// Version is the one sent in If-Match, the update fails when it is stale.
+	Version int `db:"version"`
+}
+
+func (r *UpdateProductRequest) SetDefault() {
+	// iso4217 only accepts upper case codes
+	r.Currency.Value = strings.ToUpper(r.Currency.Value)
+}
+
+func (r *UpdateProductRequest) CostumValidation() (int, map[string][]string) {
+	errors := types.NotNull(map[string]interface{ IsNull() bool }{
+		"category_id": r.CategoryId,
+		"name":        r.Name,
+		"price":       r.Price,
+		"currency":    r.Currency,
+		"stock":       r.Stock,
+	})
+
+	if len(errors) > 0 {
+		return 400, errors
+	}
+
+	errors = nil
+	return 0, errors
 }
 
 type UpdateProductResponse struct {
-	Id          string    `json:"id" db:"id"`
-	UserId      string    `json:"user_id" db:"user_id"`
-	ShopId      string    `json:"shop_id" db:"shop_id"`
-	CategoryId  string    `json:"category_id" db:"category_id"`
-	Name        string    `json:"name" db:"name"`
-	Description *string   `json:"description" db:"description"`
-	ImageUrl    *string   `json:"image_url" db:"image_url"`
-	Price       float64   `json:"price" db:"price"`
-	Stock       int       `json:"stock" db:"stock"`
-	Brand       *string   `json:"brand" validate:"omitempty,max=255,min=3" db:"brand"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	Id          string          `json:"id" db:"id"`
+	UserId      string          `json:"user_id" db:"user_id"`
+	ShopId      string          `json:"shop_id" db:"shop_id"`
+	CategoryId  string          `json:"category_id" db:"category_id"`
+	Name        string          `json:"name" db:"name"`
+	Description *string         `json:"description" db:"description"`
+	ImageUrl    *string         `json:"image_url" db:"image_url"`
+	Price       decimal.Decimal `json:"price" db:"price"`
+	Currency    string          `json:"currency" db:"currency"`
+	Stock       int             `json:"stock" db:"stock"`
+	Brand       *string         `json:"brand" validate:"omitempty,max=255,min=3" db:"brand"`
+	Version     int             `json:"-" db:"version"`
+	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+type UpdateProductStatusRequest struct {
+	UserId string `prop:"user_id" validate:"uuid"`
+
+	Id          string        `params:"id" validate:"uuid" db:"id"`
+	Status      ProductStatus `json:"status" validate:"required,oneof=draft pending_review active archived" db:"status"`
+	PublishAt   *time.Time    `json:"publish_at" db:"publish_at"`
+	UnpublishAt *time.Time    `json:"unpublish_at" db:"unpublish_at"`
+}
+
+func (r *UpdateProductStatusRequest) CostumValidation() (int, map[string][]string) {
+	return validateSchedule(r.Status, r.PublishAt, r.UnpublishAt)
+}
+
+// ReviewProductRequest is a reviewer's decision on a product pending review:
+// approved products become active, rejected ones go back to draft.
+type ReviewProductRequest struct {
+	Id      string `params:"id" validate:"uuid" db:"id"`
+	Approve *bool  `json:"approve" validate:"required"`
+}
+
+func (r *ReviewProductRequest) Status() ProductStatus {
+	if *r.Approve {
+		return ProductActive
+	}
+
+	return ProductDraft
+}
+
+// validateSchedule checks the publish window of a product. Only drafts can
+// wait for publish_at, and unpublish_at only makes sense for products that
+// are, or will become, active.
+func validateSchedule(status ProductStatus, publishAt, unpublishAt *time.Time) (int, map[string][]string) {
+	var (
+		errors = make(map[string][]string)
+		now    = time.Now()
+	)
+
+	if publishAt != nil {
+		if status != ProductDraft {
+			errors["publish_at"] = append(errors["publish_at"], "publish_at can only be set on draft products.")
+		} else if !publishAt.After(now) {
+			errors["publish_at"] = append(errors["publish_at"], "publish_at must be in the future.")
+		}
+	}
+
+	if unpublishAt != nil {
+		switch {
+		case status != ProductDraft && status != ProductActive:
+			errors["unpublish_at"] = append(errors["unpublish_at"], "unpublish_at can only be set on draft or active products.")
+		case !unpublishAt.After(now):
+			errors["unpublish_at"] = append(errors["unpublish_at"], "unpublish_at must be in the future.")
+		case publishAt != nil && !unpublishAt.After(*publishAt):
+			errors["unpublish_at"] = append(errors["unpublish_at"], "unpublish_at must be after publish_at.")
+		}
+	}
+
+	if len(errors) > 0 {
+		return 400, errors
+	}
+
+	errors = nil
+	return 0, errors
+}
+
+type ProductPricesRequest struct {
+	ProductId string `params:"id" validate:"uuid"`
+	Page      int    `query:"page" validate:"required"`
+	Paginate  int    `query:"paginate" validate:"required"`
+}
+
+func (r *ProductPricesRequest) SetDefault() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Paginate < 1 {
+		r.Paginate = 10
+	}
+}
+
+type ProductPrice struct {
+	Price     types.Money `json:"price" db:"price"`
+	CreatedAt time.Time   `json:"created_at" db:"created_at"`
+}
+
+type ProductPricesResponse struct {
+	Items []ProductPrice `json:"items"`
+	Meta  types.Meta     `json:"meta"`
+}
+
+type CreateProductSaleRequest struct {
+	UserId string `prop:"user_id" validate:"uuid" db:"created_by"`
+
+	ProductId string          `params:"id" validate:"uuid" db:"product_id"`
+	SalePrice decimal.Decimal `json:"sale_price" validate:"required,gt=0" db:"sale_price"`
+	StartAt   time.Time       `json:"start_at" validate:"required" db:"start_at"`
+	EndAt     time.Time       `json:"end_at" validate:"required,gtfield=StartAt" db:"end_at"`
+}
+
+type CreateProductSaleResponse struct {
+	Id string `json:"id" db:"id"`
+}
+
+type ProductSalesRequest struct {
+	ProductId string `params:"id" validate:"uuid"`
+}
+
+type ProductSale struct {
+	Id        string      `json:"id" db:"id"`
+	SalePrice types.Money `json:"sale_price" db:"sale_price"`
+	StartAt   time.Time   `json:"start_at" db:"start_at"`
+	EndAt     time.Time   `json:"end_at" db:"end_at"`
+	IsRunning bool        `json:"is_running" db:"is_running"`
+}
+
+type DeleteProductSaleRequest struct {
+	UserId string `prop:"user_id" validate:"uuid"`
+
+	ProductId string `params:"id" validate:"uuid"`
+	Id        string `params:"sale_id" validate:"uuid"`
+}
+
+// ScheduleResult is the number of products moved by one scheduler run.
+type ScheduleResult struct {
+	Published   int64
+	Unpublished int64
 }
 
 type ProductsRequest struct {
-	ShopId      string `query:"shop_id" validate:"omitempty,uuid"`
-	CategoryId  string `query:"category_id" validate:"omitempty,uuid"`
-	Name        string `query:"name" validate:"omitempty,max=255,min=3"`
-	Brand       string `query:"name" validate:"omitempty,max=255,min=3"`
-	PriceMinStr string `query:"price_min" validate:"omitempty,numeric,gte=0"`
-	PriceMaxStr string `query:"price_max" validate:"omitempty,numeric,gte=0"`
-	IsAvailable bool   `query:"is_available"`
+	UserId string `prop:"user_id" validate:"uuid"`
+
+	ShopId      string  `query:"shop_id" validate:"omitempty,uuid"`
+	CategoryId  string  `query:"category_id" validate:"omitempty,uuid"`
+	Name        string  `query:"name" validate:"omitempty,max=255,min=3"`
+	Brand       string  `query:"name" validate:"omitempty,max=255,min=3"`
+	PriceMinStr string  `query:"price_min" validate:"omitempty,numeric,gte=0"`
+	PriceMaxStr string  `query:"price_max" validate:"omitempty,numeric,gte=0"`
+	IsAvailable bool    `query:"is_available"`
+	Near        string  `query:"near" validate:"omitempty,max=64"`
+	RadiusKm    float64 `query:"radius_km" validate:"gt=0,lte=100"`
+	ClosedShops string  `query:"closed_shops" validate:"omitempty,oneof=hide flag"`
+	Verified    bool    `query:"verified"`
+	MinRating   float64 `query:"min_rating" validate:"omitempty,min=1,max=5"`
+	Sort        string  `query:"sort" validate:"omitempty,oneof=newest rating"`
+	Status      string  `query:"status" validate:"omitempty,oneof=draft pending_review active archived"`
+	Currency    string  `query:"currency" validate:"omitempty,iso4217"`
 
 	Page     int `query:"page" validate:"required"`
 	Paginate int `query:"paginate" validate:"required"`
 
-	PriceMin float64
-	PriceMax float64
+	PriceMin  decimal.Decimal
+	PriceMax  decimal.Decimal
+	Latitude  float64
+	Longitude float64
 }
 
 func (r *ProductsRequest) SetDefaults() {
@@ -108,18 +399,25 @@ func (r *ProductsRequest) SetDefaults() {
 	if r.Paginate < 1 {
 		r.Paginate = 10
 	}
+
+	if r.RadiusKm == 0 {
+		r.RadiusKm = 10
+	}
+
+	// iso4217 only accepts upper case codes
+	r.Currency = strings.ToUpper(r.Currency)
 }
 
 func (r *ProductsRequest) CostumValidation() (int, map[string][]string) {
 	var (
 		errors   = make(map[string][]string)
 		err      error
-		priceMin float64
-		priceMax float64
+		priceMin decimal.Decimal
+		priceMax decimal.Decimal
 	)
 
 	if r.PriceMinStr != "" {
-		priceMin, err = strconv.ParseFloat(r.PriceMinStr, 64)
+		priceMin, err = decimal.NewFromString(r.PriceMinStr)
 		if err != nil {
 			errors["price_min"] = append(errors["price_min"], "price_min must be a number.")
 		}
@@ -127,13 +425,29 @@ func (r *ProductsRequest) CostumValidation() (int, map[string][]string) {
 	}
 
 	if r.PriceMaxStr != "" {
-		priceMax, err = strconv.ParseFloat(r.PriceMaxStr, 64)
+		priceMax, err = decimal.NewFromString(r.PriceMaxStr)
 		if err != nil {
 			errors["price_max"] = append(errors["price_max"], "price_max must be a number.")
 		}
 		r.PriceMax = priceMax
 	}
 
+	if r.Near != "" {
+		// near=lat,lng
+		parts := strings.Split(r.Near, ",")
+		if len(parts) != 2 {
+			errors["near"] = append(errors["near"], "near must be in lat,lng format.")
+		} else {
+			lat, errLat := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+			lng, errLng := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+			if errLat != nil || errLng != nil || lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+				errors["near"] = append(errors["near"], "near must be in lat,lng format.")
+			}
+			r.Latitude = lat
+			r.Longitude = lng
+		}
+	}
+
 	if len(errors) > 0 {
 		return 400, errors
 	}
@@ -148,17 +462,24 @@ type ProductsResponse struct {
 }
 
 type Product struct {
-	Id         string    `json:"id" db:"id"`
-	Category   string    `json:"category" db:"category"`
-	CategoryId string    `json:"category_id" db:"category_id"`
-	ShopId     string    `json:"shop_id" db:"shop_id"`
-	ShopName   string    `json:"shop_name" db:"shop_name"`
-	Name       string    `json:"name" db:"name"`
-	Brand      *string   `json:"brand" db:"brand"`
-	ImageUrl   *string   `json:"image_url" db:"image_url"`
-	Price      float64   `json:"price" db:"price"`
-	CreatedAt  time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+	Id            string        `json:"id" db:"id"`
+	Category      string        `json:"category" db:"category"`
+	CategoryId    string        `json:"category_id" db:"category_id"`
+	ShopId        string        `json:"shop_id" db:"shop_id"`
+	ShopName      string        `json:"shop_name" db:"shop_name"`
+	ShopVerified  bool          `json:"shop_verified" db:"shop_verified"`
+	RatingAverage float64       `json:"rating_average" db:"rating_average"`
+	RatingCount   int           `json:"rating_count" db:"rating_count"`
+	Name          string        `json:"name" db:"name"`
+	Brand         *string       `json:"brand" db:"brand"`
+	ImageUrl      *string       `json:"image_url" db:"image_url"`
+	Price         types.Money   `json:"price" db:"price"`
+	OriginalPrice types.Money   `json:"original_price" db:"original_price"`
+	DistanceKm    *float64      `json:"distance_km,omitempty" db:"distance_km"`
+	ShopIsOpen    *bool         `json:"shop_is_open,omitempty" db:"shop_is_open"`
+	Status        ProductStatus `json:"status" db:"status"`
+	CreatedAt     time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at" db:"updated_at"`
 }
 
 type Meta struct {
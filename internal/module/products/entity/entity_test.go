@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"codebase-app/pkg/validator"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCurrencyIsNormalizedBeforeValidation(t *testing.T) {
+	v := validator.NewValidator()
+
+	products := ProductsRequest{UserId: "84095313-f3dc-4529-b869-24bb5c77c1a4", Currency: "usd"}
+	products.SetDefaults()
+	require.NoError(t, v.Validate(&products))
+	assert.Equal(t, "USD", products.Currency)
+
+	update := UpdateProductRequest{UserId: "84095313-f3dc-4529-b869-24bb5c77c1a4", Id: "c97081c5-6ed3-4649-b7ff-6113ecc09a4e"}
+	require.NoError(t, json.Unmarshal([]byte(`{"currency":"usd"}`), &update))
+	update.SetDefault()
+	require.NoError(t, v.Validate(&update))
+	assert.Equal(t, "USD", update.Currency.Value)
+
+	products.Currency = "dollar"
+	products.SetDefaults()
+	assert.Error(t, v.Validate(&products))
+}
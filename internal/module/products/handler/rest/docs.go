@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"codebase-app/internal/module/products/entity"
+	"codebase-app/pkg/openapi"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Operations documents the routes of Register.
+var Operations = []openapi.Operation{
+	{
+		Handler:  (*productHandler).GetProducts,
+		Summary:  "Search products",
+		Request:  entity.ProductsRequest{},
+		Response: entity.ProductsResponse{},
+	},
+	{
+		Handler:     (*productHandler).CreateProduct,
+		Summary:     "Create a product",
+		Description: "Retries with the same Idempotency-Key and body replay the first response.",
+		Request:     entity.CreateProductRequest{},
+		Response:    entity.CreateProductResponse{},
+		Status:      fiber.StatusCreated,
+	},
+	{
+		Handler:  (*productHandler).GetTrash,
+		Summary:  "List your deleted products",
+		Request:  entity.TrashRequest{},
+		Response: entity.TrashResponse{},
+	},
+	{
+		Handler:     (*productHandler).GetProduct,
+		Summary:     "Get a product",
+		Description: "Answers 304 when If-None-Match matches the ETag.",
+		Request:     entity.GetProductRequest{},
+		Response:    entity.GetProductResponse{},
+	},
+	{
+		Handler: (*productHandler).UpdateProductStatus,
+		Summary: "Change the status of a product",
+		Request: entity.UpdateProductStatusRequest{},
+	},
+	{
+		Handler:     (*productHandler).ReviewProduct,
+		Summary:     "Approve or reject a product pending review, as an admin",
+		Description: "Approved products become active, rejected ones go back to draft.",
+		Request:     entity.ReviewProductRequest{},
+	},
+	{
+		Handler:  (*productHandler).GetProductPrices,
+		Summary:  "Get the price history of a product",
+		Request:  entity.ProductPricesRequest{},
+		Response: entity.ProductPricesResponse{},
+	},
+	{
+		Handler:  (*productHandler).GetProductSales,
+		Summary:  "List the scheduled sales of a product",
+		Request:  entity.ProductSalesRequest{},
+		Response: []entity.ProductSale{},
+	},
+	{
+		Handler:  (*productHandler).CreateProductSale,
+		Summary:  "Schedule a sale price",
+		Request:  entity.CreateProductSaleRequest{},
+		Response: entity.CreateProductSaleResponse{},
+		Status:   fiber.StatusCreated,
+	},
+	{
+		Handler: (*productHandler).DeleteProductSale,
+		Summary: "Cancel a scheduled sale",
+		Request: entity.DeleteProductSaleRequest{},
+	},
+	{
+		Handler: (*productHandler).DeleteProduct,
+		Summary: "Delete a product",
+		Request: entity.DeleteProductRequest{},
+	},
+	{
+		Handler: (*productHandler).RestoreProduct,
+		Summary: "Restore a deleted product",
+		Request: entity.RestoreProductRequest{},
+	},
+	{
+		Handler:  (*productHandler).UpdateProduct,
+		Summary:  "Update a product",
+		Request:  entity.UpdateProductRequest{},
+		Response: entity.UpdateProductResponse{},
+	},
+}
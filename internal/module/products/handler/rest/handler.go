@@ -7,6 +7,7 @@ import (
 	"codebase-app/internal/module/products/ports"
 	"codebase-app/internal/module/products/repository"
 	"codebase-app/internal/module/products/service"
+	"codebase-app/pkg"
 	"codebase-app/pkg/errmsg"
 	"codebase-app/pkg/response"
 
@@ -31,33 +32,46 @@ func NewProductsHandler() *productHandler {
 
 func (h *productHandler) Register(router fiber.Router) {
 	router.Get("/", middleware.UserIdHeader, h.GetProducts)
-	router.Post("/", middleware.UserIdHeader, h.CreateProduct)
-	router.Get("/:id", h.GetProduct)
+	router.Post("/", middleware.UserIdHeader, middleware.Idempotency(), h.CreateProduct)
+	router.Get("/trash", middleware.UserIdHeader, h.GetTrash)
+	router.Get("/:id", middleware.OptionalUserIdHeader, h.GetProduct)
+	router.Put("/:id/status", middleware.UserIdHeader, h.UpdateProductStatus)
+	router.Put("/:id/review", middleware.AuthBearer, middleware.AuthRole([]string{"admin"}), h.ReviewProduct)
+	router.Get("/:id/prices", h.GetProductPrices)
+	router.Get("/:id/sales", h.GetProductSales)
+	router.Post("/:id/sales", middleware.UserIdHeader, h.CreateProductSale)
+	router.Delete("/:id/sales/:sale_id", middleware.UserIdHeader, h.DeleteProductSale)
 	router.Delete(":id", middleware.UserIdHeader, h.DeleteProduct)
-	router.Patch("/:id", middleware.UserIdHeader, h.UpdateProduct)
+	router.Post("/:id/restore", middleware.UserIdHeader, h.RestoreProduct)
+	router.Patch("/:id", middleware.UserIdHeader, middleware.IfMatch, h.UpdateProduct)
 }
 
 func (h *productHandler) CreateProduct(c *fiber.Ctx) error {
 	var (
 		req = new(entity.CreateProductRequest)
-		ctx = c.Context()
+		ctx = c.UserContext()
 		v   = adapter.Adapters.Validator
 		l   = middleware.GetLocals(c)
 	)
 
 	if err := c.BodyParser(req); err != nil {
-		log.Warn().Err(err).Msg("handler::CreateProduct - Parse request body")
+		log.Ctx(ctx).Warn().Err(err).Msg("handler::CreateProduct - Parse request body")
 		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
 	}
 
 	req.UserId = l.UserId
+	req.SetDefault()
 
 	if err := v.Validate(req); err != nil {
-		log.Warn().Err(err).Any("payload", req).Msg("handler::CreateProduct - Validate request body")
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::CreateProduct - Validate request body")
 		code, errs := errmsg.Errors(err, req)
 		return c.Status(code).JSON(response.Error(errs))
 	}
 
+	if code, errs := req.CostumValidation(); code != 0 {
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
 	resp, err := h.service.CreateProduct(ctx, req)
 	if err != nil {
 		code, errs := errmsg.Errors[error](err)
@@ -71,14 +85,21 @@ func (h *productHandler) CreateProduct(c *fiber.Ctx) error {
 func (h *productHandler) GetProduct(c *fiber.Ctx) error {
 	var (
 		req = new(entity.GetProductRequest)
-		ctx = c.Context()
+		ctx = c.UserContext()
 		v   = adapter.Adapters.Validator
 	)
 
+	// owners also see their drafts
+	c.Vary("X-USER-ID")
+
+	// GetLocals warns when the header is missing, which is expected here
+	if userId, ok := c.Locals("user_id").(string); ok {
+		req.UserId = userId
+	}
 	req.Id = c.Params("id")
 
 	if err := v.Validate(req); err != nil {
-		log.Warn().Err(err).Any("payload", req).Msg("handler::GetProduct - Validate request body")
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::GetProduct - Validate request body")
 		code, errs := errmsg.Errors(err, req)
 		return c.Status(code).JSON(response.Error(errs))
 	}
@@ -89,19 +110,21 @@ func (h *productHandler) GetProduct(c *fiber.Ctx) error {
 		return c.Status(code).JSON(response.Error(errs))
 	}
 
-	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+	return middleware.SendETagged(c, resp.Version, response.Success(resp, ""))
 }
 
 func (h *productHandler) DeleteProduct(c *fiber.Ctx) error {
 	var (
 		req = new(entity.DeleteProductRequest)
-		ctx = c.Context()
+		ctx = c.UserContext()
 		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
 	)
+	req.UserId = l.UserId
 	req.Id = c.Params("id")
 
 	if err := v.Validate(req); err != nil {
-		log.Warn().Err(err).Any("payload", req).Msg("handler::DeleteProduct - Validate request body")
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::DeleteProduct - Validate request body")
 		code, errs := errmsg.Errors(err, req)
 		return c.Status(code).JSON(response.Error(errs))
 	}
@@ -118,46 +141,56 @@ func (h *productHandler) DeleteProduct(c *fiber.Ctx) error {
 func (h *productHandler) UpdateProduct(c *fiber.Ctx) error {
 	var (
 		req = new(entity.UpdateProductRequest)
-		ctx = c.Context()
+		ctx = c.UserContext()
 		v   = adapter.Adapters.Validator
 		l   = middleware.GetLocals(c)
 	)
 
 	if err := c.BodyParser(req); err != nil {
-		log.Warn().Err(err).Msg("handler::UpdateProduct - Parse request body")
+		log.Ctx(ctx).Warn().Err(err).Msg("handler::UpdateProduct - Parse request body")
 		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
 	}
 
 	req.UserId = l.UserId
 	req.Id = c.Params("id")
+	req.Version = l.IfMatch
+	req.SetDefault()
 
 	if err := v.Validate(req); err != nil {
-		log.Warn().Err(err).Any("payload", req).Msg("handler::UpdateProduct - Validate request body")
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::UpdateProduct - Validate request body")
 		code, errs := errmsg.Errors(err, req)
 		return c.Status(code).JSON(response.Error(errs))
 	}
 
+	if code, errs := req.CostumValidation(); code != 0 {
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
 	resp, err := h.service.UpdateProduct(ctx, req)
 	if err != nil {
 		code, errs := errmsg.Errors[error](err)
 		return c.Status(code).JSON(response.Error(errs))
 	}
 
+	c.Set(fiber.HeaderETag, pkg.FormatETag(resp.Version))
+
 	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
 }
 
 func (h *productHandler) GetProducts(c *fiber.Ctx) error {
 	var (
 		req = &entity.ProductsRequest{}
-		ctx = c.Context()
+		ctx = c.UserContext()
 		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
 	)
 
 	if err := c.QueryParser(req); err != nil {
-		log.Error().Err(err).Msg("service: Failed to parse request query")
+		log.Ctx(ctx).Error().Err(err).Msg("service: Failed to parse request query")
 		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
 	}
 
+	req.UserId = l.UserId
 	req.SetDefaults()
 
 	if code, errs := req.CostumValidation(); code != 0 {
@@ -165,7 +198,7 @@ func (h *productHandler) GetProducts(c *fiber.Ctx) error {
 	}
 
 	if err := v.Validate(req); err != nil {
-		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request query")
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("service: Invalid request query")
 		code, errs := errmsg.Errors(err, req)
 		return c.Status(code).JSON(response.Error(errs))
 	}
@@ -178,3 +211,232 @@ func (h *productHandler) GetProducts(c *fiber.Ctx) error {
 
 	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
 }
+
+func (h *productHandler) UpdateProductStatus(c *fiber.Ctx) error {
+	var (
+		req = new(entity.UpdateProductStatusRequest)
+		ctx = c.UserContext()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("handler::UpdateProductStatus - Parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.UserId
+	req.Id = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::UpdateProductStatus - Validate request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if code, errs := req.CostumValidation(); code != 0 {
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if err := h.service.UpdateProductStatus(ctx, req); err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(nil, ""))
+}
+
+func (h *productHandler) ReviewProduct(c *fiber.Ctx) error {
+	var (
+		req = new(entity.ReviewProductRequest)
+		ctx = c.UserContext()
+		v   = adapter.Adapters.Validator
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("handler::ReviewProduct - Parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.Id = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::ReviewProduct - Validate request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if err := h.service.ReviewProduct(ctx, req); err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(nil, ""))
+}
+
+func (h *productHandler) GetProductPrices(c *fiber.Ctx) error {
+	var (
+		req = new(entity.ProductPricesRequest)
+		ctx = c.UserContext()
+		v   = adapter.Adapters.Validator
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("handler::GetProductPrices - Parse request query")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.ProductId = c.Params("id")
+	req.SetDefault()
+
+	if err := v.Validate(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::GetProductPrices - Validate request query")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetProductPrices(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *productHandler) GetProductSales(c *fiber.Ctx) error {
+	var (
+		req = new(entity.ProductSalesRequest)
+		ctx = c.UserContext()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.ProductId = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::GetProductSales - Validate request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetProductSales(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *productHandler) CreateProductSale(c *fiber.Ctx) error {
+	var (
+		req = new(entity.CreateProductSaleRequest)
+		ctx = c.UserContext()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("handler::CreateProductSale - Parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.UserId
+	req.ProductId = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::CreateProductSale - Validate request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.CreateProductSale(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response.Success(resp, ""))
+}
+
+func (h *productHandler) DeleteProductSale(c *fiber.Ctx) error {
+	var (
+		req = new(entity.DeleteProductSaleRequest)
+		ctx = c.UserContext()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	req.UserId = l.UserId
+	req.ProductId = c.Params("id")
+	req.Id = c.Params("sale_id")
+
+	if err := v.Validate(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::DeleteProductSale - Validate request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if err := h.service.DeleteProductSale(ctx, req); err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(nil, ""))
+}
+
+func (h *productHandler) GetTrash(c *fiber.Ctx) error {
+	var (
+		req = new(entity.TrashRequest)
+		ctx = c.UserContext()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("handler::GetTrash - Parse request query")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.UserId
+	req.SetDefault()
+
+	if err := v.Validate(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::GetTrash - Validate request query")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetTrash(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *productHandler) RestoreProduct(c *fiber.Ctx) error {
+	var (
+		req = new(entity.RestoreProductRequest)
+		ctx = c.UserContext()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	req.UserId = l.UserId
+	req.Id = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::RestoreProduct - Validate request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if err := h.service.RestoreProduct(ctx, req); err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(nil, ""))
+}
@@ -11,6 +11,16 @@ type ProductRepository interface {
 	DeleteProduct(ctx context.Context, req *entity.DeleteProductRequest) error
 	UpdateProduct(ctx context.Context, req *entity.UpdateProductRequest) (*entity.UpdateProductResponse, error)
 	GetProducts(ctx context.Context, req *entity.ProductsRequest) (entity.ProductsResponse, error)
+	UpdateProductStatus(ctx context.Context, req *entity.UpdateProductStatusRequest) error
+	ReviewProduct(ctx context.Context, req *entity.ReviewProductRequest) error
+	ApplySchedules(ctx context.Context) (*entity.ScheduleResult, error)
+	GetProductPrices(ctx context.Context, req *entity.ProductPricesRequest) (*entity.ProductPricesResponse, error)
+	CreateProductSale(ctx context.Context, req *entity.CreateProductSaleRequest) (*entity.CreateProductSaleResponse, error)
+	GetProductSales(ctx context.Context, req *entity.ProductSalesRequest) ([]entity.ProductSale, error)
+	DeleteProductSale(ctx context.Context, req *entity.DeleteProductSaleRequest) error
+	GetTrash(ctx context.Context, req *entity.TrashRequest) (*entity.TrashResponse, error)
+	RestoreProduct(ctx context.Context, req *entity.RestoreProductRequest) error
+	PurgeProducts(ctx context.Context, req *entity.PurgeRequest) (int64, error)
 }
 
 type ProductService interface {
@@ -19,4 +29,14 @@ type ProductService interface {
 	DeleteProduct(ctx context.Context, req *entity.DeleteProductRequest) error
 	UpdateProduct(ctx context.Context, req *entity.UpdateProductRequest) (*entity.UpdateProductResponse, error)
 	GetProducts(ctx context.Context, req *entity.ProductsRequest) (entity.ProductsResponse, error)
+	UpdateProductStatus(ctx context.Context, req *entity.UpdateProductStatusRequest) error
+	ReviewProduct(ctx context.Context, req *entity.ReviewProductRequest) error
+	ApplySchedules(ctx context.Context) (*entity.ScheduleResult, error)
+	GetProductPrices(ctx context.Context, req *entity.ProductPricesRequest) (*entity.ProductPricesResponse, error)
+	CreateProductSale(ctx context.Context, req *entity.CreateProductSaleRequest) (*entity.CreateProductSaleResponse, error)
+	GetProductSales(ctx context.Context, req *entity.ProductSalesRequest) ([]entity.ProductSale, error)
+	DeleteProductSale(ctx context.Context, req *entity.DeleteProductSaleRequest) error
+	GetTrash(ctx context.Context, req *entity.TrashRequest) (*entity.TrashResponse, error)
+	RestoreProduct(ctx context.Context, req *entity.RestoreProductRequest) error
+	PurgeProducts(ctx context.Context, req *entity.PurgeRequest) (int64, error)
 }
@@ -3,42 +3,59 @@ package repository
 import (
 	"codebase-app/internal/module/products/entity"
 	"codebase-app/internal/module/products/ports"
+	"codebase-app/pkg/database"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/types"
 	"context"
+	"database/sql"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
 )
 
 var _ ports.ProductRepository = &productRepository{}
 
 type productRepository struct {
-	db *sqlx.DB
+	db *database.DB
 }
 
 func NewProductRepository(db *sqlx.DB) *productRepository {
 	return &productRepository{
-		db: db,
+		db: database.New(db),
 	}
 }
 
 func (r *productRepository) CreateProduct(ctx context.Context, req *entity.CreateProductRequest) (*entity.CreateProductResponse, error) {
 	var resp = new(entity.CreateProductResponse)
 	// Your code here
+	// the first price history entry is written in the same statement
 	query := `
-		INSERT INTO
-			products (
-				shop_id,
-				category_id,
-				name,
-				description,
-				image_url,
-				price,
-				brand,
-				stock
-			)
-			VALUES ( $1, $2, $3, $4, $5, $6, $7, $8, $9 )
-			RETURNING
-				id, shop_id,category_id, name, description, image_url, price, brand, stock, created_at, updated_at
+		WITH p AS (
+			INSERT INTO
+				products (
+					shop_id,
+					category_id,
+					name,
+					description,
+					image_url,
+					price,
+					brand,
+					stock,
+					status,
+					publish_at,
+					unpublish_at,
+					currency
+				)
+				VALUES ( $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12 )
+				RETURNING
+					id, shop_id,category_id, name, description, image_url, price, currency, brand, stock, status, publish_at, unpublish_at, created_at, updated_at
+		), history AS (
+			INSERT INTO product_prices (product_id, price, currency)
+			SELECT id, price, currency FROM p
+		)
+		SELECT * FROM p
 	`
 
 	err := r.db.QueryRowContext(ctx, r.db.Rebind(query),
@@ -49,100 +66,222 @@ func (r *productRepository) CreateProduct(ctx context.Context, req *entity.Creat
 		req.ImageUrl,
 		req.Price,
 		req.Brand,
-		req.Stock).Scan(&resp.Id, &resp.ShopId, &resp.CategoryId, &resp.Name, &resp.Description, &resp.ImageUrl, &resp.Price, &resp.Brand, &resp.Stock, &resp.CreatedAt, &resp.UpdatedAt)
+		req.Stock,
+		req.Status,
+		req.PublishAt,
+		req.UnpublishAt,
+		req.Currency).Scan(&resp.Id, &resp.ShopId, &resp.CategoryId, &resp.Name, &resp.Description, &resp.ImageUrl, &resp.Price, &resp.Currency, &resp.Brand, &resp.Stock, &resp.Status, &resp.PublishAt, &resp.UnpublishAt, &resp.CreatedAt, &resp.UpdatedAt)
 	if err != nil {
-		log.Error().Err(err).Any("payload", req).Msg("repository::CreateProduct - Failed to create Product")
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::CreateProduct - Failed to create Product")
 		return nil, err
 	}
 	return resp, nil
 }
 
 func (r *productRepository) GetProduct(ctx context.Context, req *entity.GetProductRequest) (*entity.GetProductResponse, error) {
-	var resp = new(entity.GetProductResponse)
+	type dao struct {
+		entity.GetProductResponse
+		RatingAverage float64 `db:"rating_average"`
+		RatingCount   int     `db:"rating_count"`
+		Star1         int     `db:"star_1"`
+		Star2         int     `db:"star_2"`
+		Star3         int     `db:"star_3"`
+		Star4         int     `db:"star_4"`
+		Star5         int     `db:"star_5"`
+	}
+
+	var data = new(dao)
 	// Your code here
 	query := `
 		SELECT 
-		id,
-			category_id,
-			shop_id,
-			name,
-			image_url,
-			price,
-			stock,
-			brand,
-			created_at,
-			updated_at
+		p.id,
+			p.category_id,
+			p.shop_id,
+			p.name,
+			p.image_url,
+			ROW(product_effective_price(p.id, p.price), p.currency) AS price,
+			ROW(p.price, p.currency) AS original_price,
+			sale.end_at AS sale_ends_at,
+			p.stock,
+			p.brand,
+			p.status,
+			p.publish_at,
+			p.unpublish_at,
+			p.version,
+			p.created_at,
+			p.updated_at,
+			COALESCE(rs.rating_average, 0) AS rating_average,
+			COALESCE(rs.rating_count, 0) AS rating_count,
+			COALESCE(rs.star_1, 0) AS star_1,
+			COALESCE(rs.star_2, 0) AS star_2,
+			COALESCE(rs.star_3, 0) AS star_3,
+			COALESCE(rs.star_4, 0) AS star_4,
+			COALESCE(rs.star_5, 0) AS star_5
 		FROM
-			products
+			products p
+		JOIN shops s
+			ON s.id = p.shop_id
+		LEFT JOIN product_rating_summaries rs
+			ON rs.product_id = p.id
+		LEFT JOIN LATERAL (
+			SELECT ps.end_at
+			FROM product_sales ps
+			WHERE
+				ps.product_id = p.id
+				AND ps.deleted_at IS NULL
+				AND ps.start_at <= NOW()
+				AND ps.end_at > NOW()
+				AND ps.sale_price < p.price
+			ORDER BY ps.sale_price ASC
+			LIMIT 1
+		) sale ON TRUE
 		WHERE
-			deleted_at IS NULL
-		AND id = ?
+			p.deleted_at IS NULL
+		AND s.deleted_at IS NULL
+		AND p.id = ?
+		AND (p.status = 'active' OR s.user_id::TEXT = ?)
 	`
 
-	err := r.db.QueryRowxContext(ctx, r.db.Rebind(query), req.Id).StructScan(resp)
+	err := r.db.QueryRowxContext(ctx, r.db.Rebind(query), req.Id, req.UserId).StructScan(data)
 	if err != nil {
-		log.Error().Err(err).Any("payload", req).Msg("repository::GetProduct - Failed to get Product")
+		if err == sql.ErrNoRows {
+			log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("repository::GetProduct - Product not found")
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Produk tidak ditemukan"))
+		}
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::GetProduct - Failed to get Product")
 		return nil, err
 	}
 
+	resp := &data.GetProductResponse
+	resp.Rating = entity.Rating{
+		Average: data.RatingAverage,
+		Count:   data.RatingCount,
+		Histogram: map[string]int{
+			"1": data.Star1,
+			"2": data.Star2,
+			"3": data.Star3,
+			"4": data.Star4,
+			"5": data.Star5,
+		},
+	}
+
 	return resp, nil
 }
 
 func (r *productRepository) DeleteProduct(ctx context.Context, req *entity.DeleteProductRequest) error {
 	query := `
-		UPDATE products
+		UPDATE products p
 		SET deleted_at = NOW()
-		WHERE id = ?
+		FROM shops s
+		WHERE
+			p.id = ?
+			AND p.deleted_at IS NULL
+			AND s.id = p.shop_id
+			AND s.user_id = ?
 	`
 
-	_, err := r.db.ExecContext(ctx, r.db.Rebind(query), req.Id)
+	res, err := r.db.ExecContext(ctx, r.db.Rebind(query), req.Id, req.UserId)
 	if err != nil {
-		log.Error().Err(err).Any("payload", req).Msg("repository::DeleteProduct - Failed to delete Product")
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::DeleteProduct - Failed to delete Product")
 		return err
 	}
 
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		log.Ctx(ctx).Warn().Any("payload", req).Msg("repository::DeleteProduct - Product not found")
+		return errmsg.NewCustomErrors(404, errmsg.WithMessage("Produk tidak ditemukan"))
+	}
+
 	return nil
 }
 
 func (r *productRepository) UpdateProduct(ctx context.Context, req *entity.UpdateProductRequest) (*entity.UpdateProductResponse, error) {
-	var resp = new(entity.UpdateProductResponse)
+	var (
+		resp = new(entity.UpdateProductResponse)
+		set  = new(types.PatchSet)
+	)
+
+	// only the members present in the merge patch are written
+	types.AddPatch(set, "category_id", req.CategoryId)
+	types.AddPatch(set, "name", req.Name)
+	types.AddPatch(set, "description", req.Description)
+	types.AddPatch(set, "image_url", req.ImageUrl)
+	types.AddPatch(set, "brand", req.Brand)
+	types.AddPatch(set, "price", req.Price)
+	types.AddPatch(set, "currency", req.Currency)
+	types.AddPatch(set, "stock", req.Stock)
+
+	if set.Empty() {
+		log.Ctx(ctx).Warn().Any("payload", req).Msg("repository::UpdateProduct - Empty patch")
+		return nil, errmsg.NewCustomErrors(400, errmsg.WithMessage("Tidak ada data yang diubah"))
+	}
 
+	// a price history entry is only written when the price or its currency changes
 	query := `
-		UPDATE
-			products
-		SET
-			category_id = $1,
-			name = $2,
-			description = $3,
-			image_url = $4,
-			price = $5,
-			stock = $6,
-			brand = $7,
-			updated_at = NOW()
-		WHERE
-			id = $8
-			AND deleted_at IS NULL
-		RETURNING
-			id, shop_id, category_id, name, description, image_url, price, stock, brand, created_at, updated_at
+		WITH old AS (
+			SELECT id, price, currency
+			FROM products
+			WHERE
+				id = ?
+				AND deleted_at IS NULL
+			FOR UPDATE
+		), p AS (
+			UPDATE
+				products
+			SET
+				` + set.Clause() + `,
+				updated_at = NOW()
+			FROM old
+			WHERE
+				products.id = old.id
+				AND products.version = ?
+			RETURNING
+				products.id, shop_id, category_id, name, description, image_url, products.price, currency, stock, brand, products.version, created_at, updated_at
+		), history AS (
+			INSERT INTO product_prices (product_id, price, currency)
+			SELECT p.id, p.price, p.currency
+			FROM p
+			JOIN old ON old.id = p.id
+			WHERE p.price <> old.price OR p.currency <> old.currency
+		)
+		SELECT * FROM p
 	`
 
-	err := r.db.QueryRowxContext(ctx, r.db.Rebind(query),
-		req.CategoryId,
-		req.Name,
-		req.Description,
-		req.ImageUrl,
-		req.Price,
-		req.Stock,
-		req.Brand,
-		req.Id).Scan(&resp.Id, &resp.ShopId, &resp.CategoryId, &resp.Name, &resp.Description, &resp.ImageUrl, &resp.Price, &resp.Stock, &resp.Brand, &resp.CreatedAt, &resp.UpdatedAt)
+	args := append([]any{req.Id}, set.Args()...)
+	args = append(args, req.Version)
+
+	err := r.db.QueryRowxContext(ctx, r.db.Rebind(query), args...).
+		Scan(&resp.Id, &resp.ShopId, &resp.CategoryId, &resp.Name, &resp.Description, &resp.ImageUrl, &resp.Price, &resp.Currency, &resp.Stock, &resp.Brand, &resp.Version, &resp.CreatedAt, &resp.UpdatedAt)
 	if err != nil {
-		log.Error().Err(err).Any("payload", req).Msg("repository::UpdateProduct - Failed to update Product")
+		if err == sql.ErrNoRows {
+			return nil, r.updateMissError(ctx, req.Id)
+		}
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::UpdateProduct - Failed to update Product")
 		return nil, err
 	}
 
 	return resp, nil
 }
 
+// updateMissError tells a missing product apart from a stale If-Match
+// version after an optimistic update matched no row.
+func (r *productRepository) updateMissError(ctx context.Context, id string) error {
+	var exists bool
+
+	err := r.db.GetContext(ctx, &exists, r.db.Rebind("SELECT EXISTS (SELECT 1 FROM products WHERE id = ? AND deleted_at IS NULL)"), id)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("id", id).Msg("repository::updateMissError - Failed to check product")
+		return err
+	}
+
+	if !exists {
+		log.Ctx(ctx).Warn().Str("id", id).Msg("repository::UpdateProduct - Product not found")
+		return errmsg.NewCustomErrors(404, errmsg.WithMessage("Produk tidak ditemukan"))
+	}
+
+	log.Ctx(ctx).Warn().Str("id", id).Msg("repository::UpdateProduct - Version mismatch")
+	return errmsg.NewCustomErrors(412, errmsg.WithMessage("Produk telah diubah oleh pengguna lain, muat ulang lalu coba lagi"))
+}
+
 func (r *productRepository) GetProducts(ctx context.Context, req *entity.ProductsRequest) (entity.ProductsResponse, error) {
 	type dao struct {
 		TotalData int `db:"total_data"`
@@ -151,11 +290,96 @@ func (r *productRepository) GetProducts(ctx context.Context, req *entity.Product
 	var (
 		res  entity.ProductsResponse
 		data = make([]dao, 0)
-		arg  = make(map[string]any)
 	)
 	res.Meta.Page = req.Page
 	res.Meta.Paginate = req.Paginate
 
+	if req.Currency != "" {
+		var supported bool
+		err := r.db.GetContext(ctx, &supported, r.db.Rebind("SELECT EXISTS (SELECT 1 FROM currencies WHERE code = ?)"), req.Currency)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository: GetProducts failed")
+			return res, err
+		}
+		if !supported {
+			log.Ctx(ctx).Warn().Any("payload", req).Msg("repository: GetProducts unsupported currency")
+			return res, errmsg.NewCustomErrors(422, errmsg.WithErrors("currency", "Mata uang tidak didukung"))
+		}
+	}
+
+	query, arg := productsQuery(req)
+
+	err := r.db.SelectNamedContext(ctx, &data, query, arg)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository: GetProducts failed")
+		return res, err
+	}
+
+	for _, d := range data {
+		res.Items = append(res.Items, entity.Product{
+			Id:            d.Id,
+			CategoryId:    d.CategoryId,
+			Category:      d.Category,
+			ShopId:        d.ShopId,
+			ShopName:      d.ShopName,
+			ShopVerified:  d.ShopVerified,
+			RatingAverage: d.RatingAverage,
+			RatingCount:   d.RatingCount,
+			Name:          d.Name,
+			ImageUrl:      d.ImageUrl,
+			Price:         d.Price,
+			OriginalPrice: d.OriginalPrice,
+			DistanceKm:    d.DistanceKm,
+			ShopIsOpen:    d.ShopIsOpen,
+			Status:        d.Status,
+			CreatedAt:     d.CreatedAt,
+			UpdatedAt:     d.UpdatedAt,
+		})
+
+		res.Meta.TotalData = d.TotalData
+	}
+
+	res.Meta.CountTotalPage()
+	return res, nil
+
+}
+
+// productsQuery builds the named query of GetProducts. sqlx compiles it
+// and turns "::" into ":", so its casts are written with CAST.
+func productsQuery(req *entity.ProductsRequest) (string, map[string]any) {
+	arg := make(map[string]any)
+
+	distanceColumn := "CAST(NULL AS DOUBLE PRECISION) AS distance_km"
+	if req.Near != "" {
+		distanceColumn = "ST_Distance(shops.location, CAST(ST_MakePoint(:lng, :lat) AS geography)) / 1000 AS distance_km"
+		arg["lat"] = req.Latitude
+		arg["lng"] = req.Longitude
+	}
+
+	shopIsOpenColumn := "CAST(NULL AS BOOLEAN) AS shop_is_open"
+	if req.ClosedShops == "flag" {
+		shopIsOpenColumn = "shop_is_open(shops.id) AS shop_is_open"
+	}
+
+	var (
+		priceColumn         = "product_effective_price(products.id, products.price)"
+		originalPriceColumn = "products.price"
+		currencyColumn      = "products.currency"
+		currencyJoin        string
+	)
+	if req.Currency != "" {
+		// rates are relative to the base currency: amount * rate(source) / rate(target)
+		priceColumn = "ROUND(" + priceColumn + " * src.rate / dst.rate, 4)"
+		originalPriceColumn = "ROUND(products.price * src.rate / dst.rate, 4)"
+		currencyColumn = "dst.code"
+		currencyJoin = `
+		JOIN currencies src
+			ON src.code = products.currency
+		JOIN currencies dst
+			ON dst.code = :currency`
+		arg["currency"] = req.Currency
+	}
+
 	query := `
 		SELECT
 			COUNT(*) OVER() AS total_data,
@@ -164,10 +388,17 @@ func (r *productRepository) GetProducts(ctx context.Context, req *entity.Product
 			product_categories.name as category,
 			products.shop_id as shop_id,
 			shops.name as shop_name,
+			shops.verified_at IS NOT NULL as shop_verified,
+			COALESCE(rs.rating_average, 0) as rating_average,
+			COALESCE(rs.rating_count, 0) as rating_count,
 			products.name as name,
 			image_url,
-			price,
+			ROW(` + priceColumn + `, ` + currencyColumn + `) as price,
+			ROW(` + originalPriceColumn + `, ` + currencyColumn + `) as original_price,
 			brand,
+			` + distanceColumn + `,
+			` + shopIsOpenColumn + `,
+			products.status as status,
 			products.created_at as created_at,
 			products.updated_at as updated_at
 		FROM
@@ -176,9 +407,19 @@ func (r *productRepository) GetProducts(ctx context.Context, req *entity.Product
 			ON products.shop_id = shops.id
 		JOIN product_categories
 			ON products.category_id = product_categories.id
+		LEFT JOIN product_rating_summaries rs
+			ON rs.product_id = products.id` + currencyJoin + `
 		WHERE
 			products.deleted_at IS NULL
+			AND shops.deleted_at IS NULL
+			AND (products.status = 'active' OR shops.user_id = :user_id)
 	`
+	arg["user_id"] = req.UserId
+
+	if req.Status != "" {
+		query += " AND products.status = :status"
+		arg["status"] = req.Status
+	}
 
 	if req.ShopId != "" {
 		query += " AND products.shop_id = :shop_id"
@@ -201,12 +442,12 @@ func (r *productRepository) GetProducts(ctx context.Context, req *entity.Product
 	}
 
 	if req.PriceMinStr != "" {
-		query += " AND products.price >= :price_min"
+		query += " AND " + priceColumn + " >= :price_min"
 		arg["price_min"] = req.PriceMin
 	}
 
 	if req.PriceMaxStr != "" {
-		query += " AND products.price <= :price_max"
+		query += " AND " + priceColumn + " <= :price_max"
 		arg["price_max"] = req.PriceMax
 	}
 
@@ -214,45 +455,527 @@ func (r *productRepository) GetProducts(ctx context.Context, req *entity.Product
 		query += " AND products.stock > 0"
 	}
 
+	if req.Verified {
+		query += " AND shops.verified_at IS NOT NULL"
+	}
+
+	if req.ClosedShops == "hide" {
+		query += " AND shop_is_open(shops.id)"
+	}
+
+	if req.MinRating > 0 {
+		query += " AND rs.rating_average >= :min_rating"
+		arg["min_rating"] = req.MinRating
+	}
+
+	if req.Near != "" {
+		query += " AND ST_DWithin(shops.location, CAST(ST_MakePoint(:lng, :lat) AS geography), :radius_m)"
+		arg["radius_m"] = req.RadiusKm * 1000
+	}
+
+	switch {
+	case req.Sort == "rating":
+		query += " ORDER BY rating_average DESC, rating_count DESC, products.created_at DESC"
+	case req.Near != "":
+		query += " ORDER BY distance_km ASC, products.created_at DESC"
+	default:
+		query += " ORDER BY products.created_at DESC"
+	}
+
 	query += `
-		ORDER BY products.created_at DESC
 		LIMIT :paginate
 		OFFSET :offset
 	`
 	arg["paginate"] = req.Paginate
 	arg["offset"] = (req.Page - 1) * req.Paginate
 
-	nstmt, err := r.db.PrepareNamedContext(ctx, query)
+	return query, arg
+}
+
+func (r *productRepository) UpdateProductStatus(ctx context.Context, req *entity.UpdateProductStatusRequest) (err error) {
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
-		log.Error().Err(err).Any("payload", req).Msg("repository: GetProducts failed")
-		return res, err
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::UpdateProductStatus - Failed to begin transaction")
+		return err
 	}
-	defer nstmt.Close()
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				log.Ctx(ctx).Error().Err(errRollback).Any("payload", req).Msg("repository::UpdateProductStatus - Failed to rollback transaction")
+			}
+		}
+	}()
 
-	err = nstmt.SelectContext(ctx, &data, arg)
+	var current entity.ProductStatus
+
+	query := `
+		SELECT p.status
+		FROM products p
+		JOIN shops s ON s.id = p.shop_id
+		WHERE
+			p.id = ?
+			AND p.deleted_at IS NULL
+			AND s.user_id = ?
+		FOR UPDATE OF p
+	`
+
+	err = tx.QueryRowxContext(ctx, tx.Rebind(query), req.Id, req.UserId).Scan(&current)
 	if err != nil {
-		log.Error().Err(err).Any("payload", req).Msg("repository: GetProducts failed")
-		return res, err
+		if err == sql.ErrNoRows {
+			log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("repository::UpdateProductStatus - Product not found")
+			err = errmsg.NewCustomErrors(404, errmsg.WithMessage("Produk tidak ditemukan"))
+			return err
+		}
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::UpdateProductStatus - Failed to get product")
+		return err
+	}
+
+	if !current.CanTransitionTo(req.Status) {
+		log.Ctx(ctx).Warn().Any("payload", req).Str("current", string(current)).Msg("repository::UpdateProductStatus - Invalid transition")
+		err = errmsg.NewCustomErrors(409, errmsg.WithMessage("Status produk tidak dapat diubah dari "+string(current)+" ke "+string(req.Status)))
+		return err
+	}
+
+	// the schedule is always replaced, moving a product by hand clears it
+	query = `
+		UPDATE products
+		SET
+			status = ?,
+			publish_at = ?,
+			unpublish_at = ?,
+			updated_at = NOW()
+		WHERE id = ?
+	`
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(query), req.Status, req.PublishAt, req.UnpublishAt, req.Id)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::UpdateProductStatus - Failed to update product status")
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::UpdateProductStatus - Failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
+
+func (r *productRepository) ReviewProduct(ctx context.Context, req *entity.ReviewProductRequest) error {
+	var current entity.ProductStatus
+
+	// the row is only changed while it is pending, the old status tells a
+	// missing product from one that is not pending anymore
+	query := `
+		WITH old AS (
+			SELECT id, status
+			FROM products
+			WHERE id = ? AND deleted_at IS NULL
+			FOR UPDATE
+		), updated AS (
+			UPDATE products p
+			SET status = ?, updated_at = NOW()
+			FROM old
+			WHERE p.id = old.id AND old.status = 'pending_review'
+		)
+		SELECT status FROM old
+	`
+
+	err := r.db.QueryRowxContext(ctx, r.db.Rebind(query), req.Id, req.Status()).Scan(&current)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("repository::ReviewProduct - Product not found")
+			return errmsg.NewCustomErrors(404, errmsg.WithMessage("Produk tidak ditemukan"))
+		}
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::ReviewProduct - Failed to review product")
+		return err
+	}
+
+	if current != entity.ProductPendingReview {
+		log.Ctx(ctx).Warn().Any("payload", req).Str("current", string(current)).Msg("repository::ReviewProduct - Product is not pending review")
+		return errmsg.NewCustomErrors(409, errmsg.WithMessage("Produk tidak sedang menunggu review"))
+	}
+
+	return nil
+}
+
+func (r *productRepository) ApplySchedules(ctx context.Context) (*entity.ScheduleResult, error) {
+	var resp = new(entity.ScheduleResult)
+
+	query := `
+		UPDATE products
+		SET status = 'active', updated_at = NOW()
+		WHERE
+			status = 'draft'
+			AND publish_at <= NOW()
+			AND deleted_at IS NULL
+	`
+
+	res, err := r.db.ExecContext(ctx, query)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("repository::ApplySchedules - Failed to publish scheduled products")
+		return nil, err
+	}
+	resp.Published, _ = res.RowsAffected()
+
+	query = `
+		UPDATE products
+		SET status = 'archived', updated_at = NOW()
+		WHERE
+			status = 'active'
+			AND unpublish_at <= NOW()
+			AND deleted_at IS NULL
+	`
+
+	res, err = r.db.ExecContext(ctx, query)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("repository::ApplySchedules - Failed to unpublish expired products")
+		return nil, err
+	}
+	resp.Unpublished, _ = res.RowsAffected()
+
+	return resp, nil
+}
+
+func (r *productRepository) GetProductPrices(ctx context.Context, req *entity.ProductPricesRequest) (*entity.ProductPricesResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.ProductPrice
+	}
+
+	var (
+		resp = new(entity.ProductPricesResponse)
+		data = make([]dao, 0, req.Paginate)
+	)
+	resp.Items = make([]entity.ProductPrice, 0, req.Paginate)
+
+	query := `
+		SELECT
+			COUNT(pp.id) OVER() as total_data,
+			ROW(pp.price, pp.currency) AS price,
+			pp.created_at
+		FROM product_prices pp
+		JOIN products p ON p.id = pp.product_id
+		WHERE
+			pp.product_id = ?
+			AND p.deleted_at IS NULL
+			AND p.status = 'active'
+		ORDER BY pp.created_at DESC
+		LIMIT ? OFFSET ?
+	`
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query),
+		req.ProductId,
+		req.Paginate,
+		req.Paginate*(req.Page-1),
+	)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::GetProductPrices - Failed to get product prices")
+		return nil, err
+	}
+
+	if len(data) > 0 {
+		resp.Meta.TotalData = data[0].TotalData
 	}
 
 	for _, d := range data {
-		res.Items = append(res.Items, entity.Product{
-			Id:         d.Id,
-			CategoryId: d.CategoryId,
-			Category:   d.Category,
-			ShopId:     d.ShopId,
-			ShopName:   d.ShopName,
-			Name:       d.Name,
-			ImageUrl:   d.ImageUrl,
-			Price:      d.Price,
-			CreatedAt:  d.CreatedAt,
-			UpdatedAt:  d.UpdatedAt,
-		})
+		resp.Items = append(resp.Items, d.ProductPrice)
+	}
 
-		res.Meta.TotalData = d.TotalData
+	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
+
+	return resp, nil
+}
+
+func (r *productRepository) CreateProductSale(ctx context.Context, req *entity.CreateProductSaleRequest) (resp *entity.CreateProductSaleResponse, err error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::CreateProductSale - Failed to begin transaction")
+		return nil, err
 	}
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				log.Ctx(ctx).Error().Err(errRollback).Any("payload", req).Msg("repository::CreateProductSale - Failed to rollback transaction")
+			}
+		}
+	}()
 
-	res.Meta.CountTotalPage()
-	return res, nil
+	var price decimal.Decimal
+
+	// lock the product so two overlapping sales cannot be created concurrently
+	query := `
+		SELECT p.price
+		FROM products p
+		JOIN shops s ON s.id = p.shop_id
+		WHERE
+			p.id = ?
+			AND p.deleted_at IS NULL
+			AND s.user_id = ?
+		FOR UPDATE OF p
+	`
+
+	err = tx.QueryRowxContext(ctx, tx.Rebind(query), req.ProductId, req.UserId).Scan(&price)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("repository::CreateProductSale - Product not found")
+			err = errmsg.NewCustomErrors(404, errmsg.WithMessage("Produk tidak ditemukan"))
+			return nil, err
+		}
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::CreateProductSale - Failed to get product")
+		return nil, err
+	}
+
+	if req.SalePrice.GreaterThanOrEqual(price) {
+		log.Ctx(ctx).Warn().Any("payload", req).Stringer("price", price).Msg("repository::CreateProductSale - Sale price is not lower than price")
+		err = errmsg.NewCustomErrors(400, errmsg.WithErrors("sale_price", "Harga promo harus lebih rendah dari harga produk"))
+		return nil, err
+	}
+
+	var overlap bool
+
+	query = `
+		SELECT EXISTS (
+			SELECT 1
+			FROM product_sales
+			WHERE
+				product_id = ?
+				AND deleted_at IS NULL
+				AND start_at < ?
+				AND end_at > ?
+		)
+	`
+
+	err = tx.QueryRowxContext(ctx, tx.Rebind(query), req.ProductId, req.EndAt, req.StartAt).Scan(&overlap)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::CreateProductSale - Failed to check overlapping sales")
+		return nil, err
+	}
+
+	if overlap {
+		log.Ctx(ctx).Warn().Any("payload", req).Msg("repository::CreateProductSale - Overlapping sale")
+		err = errmsg.NewCustomErrors(409, errmsg.WithMessage("Jadwal promo bertabrakan dengan promo lain"))
+		return nil, err
+	}
+
+	resp = new(entity.CreateProductSaleResponse)
+
+	query = `
+		INSERT INTO product_sales (product_id, sale_price, start_at, end_at, created_by)
+		VALUES (?, ?, ?, ?, ?)
+		RETURNING id
+	`
+
+	err = tx.QueryRowxContext(ctx, tx.Rebind(query), req.ProductId, req.SalePrice, req.StartAt, req.EndAt, req.UserId).Scan(&resp.Id)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::CreateProductSale - Failed to create sale")
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::CreateProductSale - Failed to commit transaction")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *productRepository) GetProductSales(ctx context.Context, req *entity.ProductSalesRequest) ([]entity.ProductSale, error) {
+	var resp = make([]entity.ProductSale, 0)
+
+	// ended sales are part of the price history, only current and upcoming ones are listed
+	query := `
+		SELECT
+			ps.id,
+			ROW(ps.sale_price, p.currency) AS sale_price,
+			ps.start_at,
+			ps.end_at,
+			ps.start_at <= NOW() AS is_running
+		FROM product_sales ps
+		JOIN products p ON p.id = ps.product_id
+		WHERE
+			ps.product_id = ?
+			AND ps.deleted_at IS NULL
+			AND ps.end_at > NOW()
+			AND p.deleted_at IS NULL
+			AND p.status = 'active'
+		ORDER BY ps.start_at ASC
+	`
+
+	err := r.db.SelectContext(ctx, &resp, r.db.Rebind(query), req.ProductId)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::GetProductSales - Failed to get product sales")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *productRepository) DeleteProductSale(ctx context.Context, req *entity.DeleteProductSaleRequest) error {
+	query := `
+		UPDATE product_sales ps
+		SET deleted_at = NOW()
+		FROM products p
+		JOIN shops s ON s.id = p.shop_id
+		WHERE
+			ps.id = ?
+			AND ps.product_id = ?
+			AND ps.deleted_at IS NULL
+			AND p.id = ps.product_id
+			AND s.user_id = ?
+	`
+
+	res, err := r.db.ExecContext(ctx, r.db.Rebind(query), req.Id, req.ProductId, req.UserId)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::DeleteProductSale - Failed to delete sale")
+		return err
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		log.Ctx(ctx).Warn().Any("payload", req).Msg("repository::DeleteProductSale - Sale not found")
+		return errmsg.NewCustomErrors(404, errmsg.WithMessage("Promo tidak ditemukan"))
+	}
+
+	return nil
+}
+
+func (r *productRepository) GetTrash(ctx context.Context, req *entity.TrashRequest) (*entity.TrashResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.TrashItem
+	}
+
+	var (
+		resp = new(entity.TrashResponse)
+		data = make([]dao, 0, req.Paginate)
+	)
+	resp.Items = make([]entity.TrashItem, 0, req.Paginate)
+
+	query := `
+		SELECT
+			COUNT(p.id) OVER() as total_data,
+			p.id,
+			p.shop_id,
+			p.category_id,
+			p.name,
+			p.image_url,
+			p.deleted_at
+		FROM products p
+		JOIN shops s ON s.id = p.shop_id
+		WHERE
+			p.deleted_at IS NOT NULL
+			AND s.user_id = ?
+		ORDER BY p.deleted_at DESC
+		LIMIT ? OFFSET ?
+	`
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query),
+		req.UserId,
+		req.Paginate,
+		req.Paginate*(req.Page-1),
+	)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::GetTrash - Failed to get deleted products")
+		return nil, err
+	}
+
+	if len(data) > 0 {
+		resp.Meta.TotalData = data[0].TotalData
+	}
+
+	for _, d := range data {
+		resp.Items = append(resp.Items, d.TrashItem)
+	}
+
+	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
+
+	return resp, nil
+}
+
+func (r *productRepository) RestoreProduct(ctx context.Context, req *entity.RestoreProductRequest) error {
+	// a product can only come back while its shop still exists
+	query := `
+		UPDATE products p
+		SET deleted_at = NULL, updated_at = NOW()
+		FROM shops s
+		WHERE
+			p.id = ?
+			AND p.deleted_at IS NOT NULL
+			AND s.id = p.shop_id
+			AND s.user_id = ?
+			AND s.deleted_at IS NULL
+	`
+
+	res, err := r.db.ExecContext(ctx, r.db.Rebind(query), req.Id, req.UserId)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::RestoreProduct - Failed to restore product")
+		return err
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		log.Ctx(ctx).Warn().Any("payload", req).Msg("repository::RestoreProduct - Deleted product not found")
+		return errmsg.NewCustomErrors(404, errmsg.WithMessage("Produk tidak ditemukan di tempat sampah"))
+	}
+
+	return nil
+}
+
+func (r *productRepository) PurgeProducts(ctx context.Context, req *entity.PurgeRequest) (affected int64, err error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::PurgeProducts - Failed to begin transaction")
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				log.Ctx(ctx).Error().Err(errRollback).Any("payload", req).Msg("repository::PurgeProducts - Failed to rollback transaction")
+			}
+		}
+	}()
+
+	var ids []string
+	err = tx.SelectContext(ctx, &ids, tx.Rebind(`
+		SELECT id
+		FROM products
+		WHERE deleted_at IS NOT NULL AND deleted_at < ?
+		FOR UPDATE
+	`), req.Before)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::PurgeProducts - Failed to get products to purge")
+		return 0, err
+	}
+
+	if len(ids) == 0 {
+		err = tx.Commit()
+		return 0, err
+	}
+
+	// rows referencing the products go first, flags and reports cascade
+	for _, table := range []string{
+		"product_reviews",
+		"product_rating_summaries",
+		"product_inquiries",
+		"product_prices",
+		"product_sales",
+	} {
+		query := "DELETE FROM " + table + " WHERE product_id = ANY(?)"
+		if _, err = tx.ExecContext(ctx, tx.Rebind(query), pq.Array(ids)); err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("table", table).Msg("repository::PurgeProducts - Failed to delete product rows")
+			return 0, err
+		}
+	}
+
+	res, err := tx.ExecContext(ctx, tx.Rebind(`DELETE FROM products WHERE id = ANY(?)`), pq.Array(ids))
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::PurgeProducts - Failed to delete products")
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::PurgeProducts - Failed to commit transaction")
+		return 0, err
+	}
 
+	return res.RowsAffected()
 }
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"codebase-app/internal/module/products/entity"
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMock(t *testing.T) (*productRepository, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	return NewProductRepository(sqlx.NewDb(db, "postgres")), mock
+}
+
+func TestGetProductsSortByRating(t *testing.T) {
+	repo, mock := newMock(t)
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{
+		"total_data", "id", "category_id", "category", "shop_id", "shop_name", "shop_verified",
+		"rating_average", "rating_count", "name", "image_url", "price", "original_price", "brand",
+		"distance_km", "shop_is_open", "status", "created_at", "updated_at",
+	}).
+		AddRow(2, "p1", "c1", "Baju", "s1", "Toko", true, 4.5, 12, "Gamis", nil, "(100000.0000,IDR)", "(100000.0000,IDR)", nil, nil, nil, "active", now, now).
+		AddRow(2, "p2", "c1", "Baju", "s1", "Toko", true, 0.0, 0, "Kemeja", nil, "(50000.0000,IDR)", "(50000.0000,IDR)", nil, nil, nil, "active", now, now)
+
+	mock.ExpectQuery(regexp.QuoteMeta("ORDER BY rating_average DESC, rating_count DESC")).
+		WillReturnRows(rows)
+
+	res, err := repo.GetProducts(context.Background(), &entity.ProductsRequest{
+		UserId:   "84095313-f3dc-4529-b869-24bb5c77c1a4",
+		Sort:     "rating",
+		Page:     1,
+		Paginate: 10,
+	})
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	require.Len(t, res.Items, 2)
+	assert.Equal(t, 4.5, res.Items[0].RatingAverage)
+	assert.Equal(t, 12, res.Items[0].RatingCount)
+	assert.Equal(t, "100000", res.Items[0].Price.Amount.String())
+	assert.Equal(t, 2, res.Meta.TotalData)
+}
+
+// TestProductsQueryCompiles compiles the named query like PrepareNamedContext
+// does, a "::" cast would come out as a single ":".
+func TestProductsQueryCompiles(t *testing.T) {
+	tests := []struct {
+		name string
+		req  entity.ProductsRequest
+	}{
+		{"default", entity.ProductsRequest{}},
+		{"flag closed shops", entity.ProductsRequest{ClosedShops: "flag"}},
+		{"near", entity.ProductsRequest{Near: "-6.2,106.8", Latitude: -6.2, Longitude: 106.8, RadiusKm: 10}},
+		{"currency", entity.ProductsRequest{Currency: "USD", PriceMinStr: "1", PriceMaxStr: "10"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.Page, tt.req.Paginate = 1, 10
+
+			query, arg := productsQuery(&tt.req)
+			compiled, _, err := sqlx.Named(query, arg)
+			require.NoError(t, err)
+			compiled = sqlx.Rebind(sqlx.DOLLAR, compiled)
+
+			assert.NotContains(t, compiled, ":")
+			if tt.req.Near != "" {
+				assert.Regexp(t, `CAST\(ST_MakePoint\(\$\d+, \$\d+\) AS geography\)`, compiled)
+			} else {
+				assert.Contains(t, compiled, "CAST(NULL AS DOUBLE PRECISION) AS distance_km")
+			}
+			if tt.req.ClosedShops != "flag" {
+				assert.Contains(t, compiled, "CAST(NULL AS BOOLEAN) AS shop_is_open")
+			}
+		})
+	}
+}
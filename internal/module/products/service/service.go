@@ -1,6 +1,7 @@
 package service
 
 import (
+	"codebase-app/internal/infrastructure/metrics"
 	"codebase-app/internal/module/products/entity"
 	"codebase-app/internal/module/products/ports"
 	"context"
@@ -19,7 +20,13 @@ func NewProductService(repo ports.ProductRepository) *productService {
 }
 
 func (s *productService) CreateProduct(ctx context.Context, req *entity.CreateProductRequest) (*entity.CreateProductResponse, error) {
-	return s.repo.CreateProduct(ctx, req)
+	resp, err := s.repo.CreateProduct(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics.ProductsCreated.Inc()
+	return resp, nil
 }
 
 func (s *productService) GetProduct(ctx context.Context, req *entity.GetProductRequest) (*entity.GetProductResponse, error) {
@@ -37,3 +44,66 @@ func (s *productService) UpdateProduct(ctx context.Context, req *entity.UpdatePr
 func (s *productService) GetProducts(ctx context.Context, req *entity.ProductsRequest) (entity.ProductsResponse, error) {
 	return s.repo.GetProducts(ctx, req)
 }
+
+func (s *productService) UpdateProductStatus(ctx context.Context, req *entity.UpdateProductStatusRequest) error {
+	if err := s.repo.UpdateProductStatus(ctx, req); err != nil {
+		return err
+	}
+
+	metrics.ProductStatusChanges.WithLabelValues(string(req.Status)).Inc()
+	return nil
+}
+
+func (s *productService) ReviewProduct(ctx context.Context, req *entity.ReviewProductRequest) error {
+	if err := s.repo.ReviewProduct(ctx, req); err != nil {
+		return err
+	}
+
+	metrics.ProductStatusChanges.WithLabelValues(string(req.Status())).Inc()
+	return nil
+}
+
+func (s *productService) ApplySchedules(ctx context.Context) (*entity.ScheduleResult, error) {
+	resp, err := s.repo.ApplySchedules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics.ProductStatusChanges.WithLabelValues(string(entity.ProductActive)).Add(float64(resp.Published))
+	metrics.ProductStatusChanges.WithLabelValues(string(entity.ProductArchived)).Add(float64(resp.Unpublished))
+	return resp, nil
+}
+
+func (s *productService) GetProductPrices(ctx context.Context, req *entity.ProductPricesRequest) (*entity.ProductPricesResponse, error) {
+	return s.repo.GetProductPrices(ctx, req)
+}
+
+func (s *productService) CreateProductSale(ctx context.Context, req *entity.CreateProductSaleRequest) (*entity.CreateProductSaleResponse, error) {
+	resp, err := s.repo.CreateProductSale(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics.ProductSalesCreated.Inc()
+	return resp, nil
+}
+
+func (s *productService) GetProductSales(ctx context.Context, req *entity.ProductSalesRequest) ([]entity.ProductSale, error) {
+	return s.repo.GetProductSales(ctx, req)
+}
+
+func (s *productService) DeleteProductSale(ctx context.Context, req *entity.DeleteProductSaleRequest) error {
+	return s.repo.DeleteProductSale(ctx, req)
+}
+
+func (s *productService) GetTrash(ctx context.Context, req *entity.TrashRequest) (*entity.TrashResponse, error) {
+	return s.repo.GetTrash(ctx, req)
+}
+
+func (s *productService) RestoreProduct(ctx context.Context, req *entity.RestoreProductRequest) error {
+	return s.repo.RestoreProduct(ctx, req)
+}
+
+func (s *productService) PurgeProducts(ctx context.Context, req *entity.PurgeRequest) (int64, error) {
+	return s.repo.PurgeProducts(ctx, req)
+}
@@ -0,0 +1,74 @@
+package worker
+
+import (
+	"codebase-app/internal/adapter"
+	"codebase-app/internal/module/products/ports"
+	"codebase-app/internal/module/products/repository"
+	"codebase-app/internal/module/products/service"
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// defaultInterval replaces an interval time.NewTicker would panic on.
+const defaultInterval = time.Minute
+
+type scheduler struct {
+	service  ports.ProductService
+	interval time.Duration
+}
+
+// NewScheduler creates the worker that publishes drafts whose publish_at
+// has passed and archives active products whose unpublish_at has passed.
+// An interval that is not positive falls back to a minute.
+func NewScheduler(interval time.Duration) *scheduler {
+	if interval <= 0 {
+		log.Warn().Dur("interval", interval).Dur("default", defaultInterval).Msg("worker::scheduler - Invalid interval, using the default")
+		interval = defaultInterval
+	}
+
+	var (
+		repo    = repository.NewProductRepository(adapter.Adapters.ShopeefunPostgres)
+		service = service.NewProductService(repo)
+	)
+
+	return &scheduler{
+		service:  service,
+		interval: interval,
+	}
+}
+
+// Start runs the scheduler until ctx is cancelled.
+func (s *scheduler) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	log.Info().Dur("interval", s.interval).Msg("worker::scheduler - Product scheduler started")
+
+	for {
+		s.run(ctx)
+
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("worker::scheduler - Product scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *scheduler) run(ctx context.Context) {
+	res, err := s.service.ApplySchedules(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("worker::scheduler - Failed to apply product schedules")
+		return
+	}
+
+	if res.Published > 0 || res.Unpublished > 0 {
+		log.Info().
+			Int64("published", res.Published).
+			Int64("unpublished", res.Unpublished).
+			Msg("worker::scheduler - Product schedules applied")
+	}
+}
@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+type HitRequest struct {
+	// Key identifies the client and route group, e.g. "write:user:<id>".
+	Key    string
+	Window time.Duration
+}
+
+type HitResponse struct {
+	// Hits is the number of requests in the current window, this one included.
+	Hits    int       `db:"hits"`
+	ResetAt time.Time `db:"reset_at"`
+}
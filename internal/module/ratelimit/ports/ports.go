@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"codebase-app/internal/module/ratelimit/entity"
+	"context"
+)
+
+// RateLimitStore keeps the request counts of the rate limiter.
+type RateLimitStore interface {
+	// Hit counts a request for req.Key, starting a new window of req.Window
+	// when the key has none or its window is over.
+	Hit(ctx context.Context, req *entity.HitRequest) (*entity.HitResponse, error)
+	DeleteExpired(ctx context.Context) (int64, error)
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"codebase-app/internal/module/ratelimit/entity"
+	"codebase-app/internal/module/ratelimit/ports"
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+var _ ports.RateLimitStore = &memoryStore{}
+
+type memoryWindow struct {
+	key string
+	entity.HitResponse
+}
+
+type memoryStore struct {
+	mu      sync.Mutex
+	maxKeys int
+	windows map[string]*list.Element
+	// order holds the windows by start, the oldest first
+	order *list.List
+}
+
+// NewMemoryStore keeps the counts in this process, each replica limits on
+// its own. At most maxKeys clients are counted, a new one drops the oldest
+// window so the store cannot grow without bound.
+func NewMemoryStore(maxKeys int) *memoryStore {
+	return &memoryStore{
+		maxKeys: maxKeys,
+		windows: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (r *memoryStore) Hit(ctx context.Context, req *entity.HitRequest) (*entity.HitResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	e, ok := r.windows[req.Key]
+	if ok && !e.Value.(*memoryWindow).ResetAt.After(now) {
+		r.remove(e)
+		ok = false
+	}
+
+	if !ok {
+		r.deleteExpired(now)
+		for r.order.Len() >= r.maxKeys {
+			r.remove(r.order.Front())
+		}
+
+		e = r.order.PushBack(&memoryWindow{
+			key:         req.Key,
+			HitResponse: entity.HitResponse{ResetAt: now.Add(req.Window)},
+		})
+		r.windows[req.Key] = e
+	}
+
+	w := e.Value.(*memoryWindow)
+	w.Hits++
+
+	resp := w.HitResponse
+	return &resp, nil
+}
+
+func (r *memoryStore) DeleteExpired(ctx context.Context) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.deleteExpired(time.Now()), nil
+}
+
+// deleteExpired removes the windows that ended before now. Windows are
+// started in order, so it stops at the first one still running; one that
+// outlives a shorter window reloaded after it is left for a later call.
+func (r *memoryStore) deleteExpired(now time.Time) int64 {
+	var deleted int64
+	for e := r.order.Front(); e != nil && !e.Value.(*memoryWindow).ResetAt.After(now); e = r.order.Front() {
+		r.remove(e)
+		deleted++
+	}
+
+	return deleted
+}
+
+func (r *memoryStore) remove(e *list.Element) {
+	r.order.Remove(e)
+	delete(r.windows, e.Value.(*memoryWindow).key)
+}
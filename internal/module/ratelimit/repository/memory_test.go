@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"codebase-app/internal/module/ratelimit/entity"
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStoreMaxKeys(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		store = NewMemoryStore(3)
+	)
+
+	for i := 0; i < 10; i++ {
+		_, err := store.Hit(ctx, &entity.HitRequest{Key: "ip:" + strconv.Itoa(i), Window: time.Minute})
+		require.NoError(t, err)
+	}
+
+	assert.Len(t, store.windows, 3)
+	assert.Equal(t, 3, store.order.Len())
+	assert.Contains(t, store.windows, "ip:9")
+	assert.NotContains(t, store.windows, "ip:0")
+}
+
+func TestMemoryStoreWindow(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		store = NewMemoryStore(10)
+		req   = &entity.HitRequest{Key: "ip:1", Window: 20 * time.Millisecond}
+	)
+
+	for want := 1; want <= 3; want++ {
+		hit, err := store.Hit(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, want, hit.Hits)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	hit, err := store.Hit(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, 1, hit.Hits, "a new window starts once the last one ended")
+
+	time.Sleep(30 * time.Millisecond)
+
+	deleted, err := store.DeleteExpired(ctx)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, deleted)
+	assert.Empty(t, store.windows)
+}
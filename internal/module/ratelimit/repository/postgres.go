@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"codebase-app/internal/module/ratelimit/entity"
+	"codebase-app/internal/module/ratelimit/ports"
+	"codebase-app/pkg/database"
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+var _ ports.RateLimitStore = &postgresStore{}
+
+type postgresStore struct {
+	db *database.DB
+}
+
+// NewPostgresStore keeps the counts in the rate_limits table, so all
+// replicas share them.
+func NewPostgresStore(db *sqlx.DB) *postgresStore {
+	return &postgresStore{
+		db: database.New(db),
+	}
+}
+
+func (r *postgresStore) Hit(ctx context.Context, req *entity.HitRequest) (*entity.HitResponse, error) {
+	var resp = new(entity.HitResponse)
+
+	// one statement, so concurrent hits on the same key never lose a count
+	query := `
+		INSERT INTO rate_limits (key, hits, reset_at)
+		VALUES (?, 1, NOW() + make_interval(secs => ?))
+		ON CONFLICT (key) DO UPDATE
+		SET
+			hits = CASE WHEN rate_limits.reset_at <= NOW() THEN 1 ELSE rate_limits.hits + 1 END,
+			reset_at = CASE WHEN rate_limits.reset_at <= NOW() THEN EXCLUDED.reset_at ELSE rate_limits.reset_at END
+		RETURNING hits, reset_at
+	`
+
+	err := r.db.GetContext(ctx, resp, r.db.Rebind(query), req.Key, req.Window.Seconds())
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("key", req.Key).Msg("repository::Hit - Failed to count request")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *postgresStore) DeleteExpired(ctx context.Context) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM rate_limits WHERE reset_at <= NOW()`)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("repository::DeleteExpired - Failed to delete expired rate limits")
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
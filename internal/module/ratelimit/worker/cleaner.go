@@ -0,0 +1,53 @@
+package worker
+
+import (
+	"codebase-app/internal/module/ratelimit/ports"
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+type cleaner struct {
+	store    ports.RateLimitStore
+	interval time.Duration
+}
+
+// NewCleaner creates the worker that deletes the counts of rate limit
+// windows that are over.
+func NewCleaner(store ports.RateLimitStore, interval time.Duration) *cleaner {
+	return &cleaner{
+		store:    store,
+		interval: interval,
+	}
+}
+
+// Start runs the cleaner until ctx is cancelled.
+func (w *cleaner) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	log.Info().Dur("interval", w.interval).Msg("worker::cleaner - Rate limit cleaner started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("worker::cleaner - Rate limit cleaner stopped")
+			return
+		case <-ticker.C:
+			w.run(ctx)
+		}
+	}
+}
+
+func (w *cleaner) run(ctx context.Context) {
+	deleted, err := w.store.DeleteExpired(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("worker::cleaner - Failed to delete expired rate limits")
+		return
+	}
+
+	if deleted > 0 {
+		log.Debug().Int64("deleted", deleted).Msg("worker::cleaner - Expired rate limits deleted")
+	}
+}
@@ -1,27 +1,88 @@
 package entity
 
-import "codebase-app/pkg/types"
+import (
+	"codebase-app/pkg/types"
+	"strconv"
+	"time"
+)
 
 type CreateShopRequest struct {
 	UserId string `validate:"uuid" db:"user_id"`
 
-	Name        string `json:"name" validate:"required" db:"name"`
-	Description string `json:"description" validate:"required,max=255" db:"description"`
-	Terms       string `json:"terms" validate:"required" db:"terms"`
+	Name        string   `json:"name" validate:"required" db:"name"`
+	Slug        string   `json:"slug" validate:"omitempty,max=100,slug" db:"slug"`
+	Description string   `json:"description" validate:"required,max=255" db:"description"`
+	Terms       string   `json:"terms" validate:"required" db:"terms"`
+	LogoUrl     *string  `json:"logo_url" validate:"omitempty,url" db:"logo_url"`
+	BannerUrl   *string  `json:"banner_url" validate:"omitempty,url" db:"banner_url"`
+	Phone       *string  `json:"phone" validate:"omitempty,e164" db:"phone"`
+	Email       *string  `json:"email" validate:"omitempty,email" db:"email"`
+	Address     *string  `json:"address" validate:"omitempty,max=500" db:"address"`
+	City        *string  `json:"city" validate:"omitempty,max=255" db:"city"`
+	Province    *string  `json:"province" validate:"omitempty,max=255" db:"province"`
+	PostalCode  *string  `json:"postal_code" validate:"omitempty,numeric,max=16" db:"postal_code"`
+	Latitude    *float64 `json:"latitude" validate:"required_with=Longitude,omitempty,latitude"`
+	Longitude   *float64 `json:"longitude" validate:"required_with=Latitude,omitempty,longitude"`
+}
+
+// Location returns the PostGIS point of the shop, or nil when no coordinate is given.
+func (r *CreateShopRequest) Location() *types.Point {
+	if r.Latitude == nil || r.Longitude == nil {
+		return nil
+	}
+
+	p := types.NewPoint(*r.Latitude, *r.Longitude)
+	return &p
 }
 
 type CreateShopResponse struct {
-	Id string `json:"id" db:"id"`
+	Id   string `json:"id" db:"id"`
+	Slug string `json:"slug" db:"slug"`
 }
 
 type GetShopRequest struct {
 	Id string `validate:"uuid" db:"id"`
 }
 
+type GetShopBySlugRequest struct {
+	Slug string `params:"slug" validate:"required,max=100,slug" db:"slug"`
+}
+
 type GetShopResponse struct {
-	Name        string `json:"name" db:"name"`
-	Description string `json:"description" db:"description"`
-	Terms       string `json:"terms" db:"terms"`
+	Id           string        `json:"id" db:"id"`
+	Name         string        `json:"name" db:"name"`
+	Slug         string        `json:"slug" db:"slug"`
+	Description  string        `json:"description" db:"description"`
+	Terms        string        `json:"terms" db:"terms"`
+	LogoUrl      *string       `json:"logo_url" db:"logo_url"`
+	BannerUrl    *string       `json:"banner_url" db:"banner_url"`
+	Phone        *string       `json:"phone" db:"phone"`
+	Email        *string       `json:"email" db:"email"`
+	Address      ShopAddress   `json:"address"`
+	Location     *ShopLocation `json:"location"`
+	ProductCount int           `json:"product_count" db:"product_count"`
+	Rating       ShopRating    `json:"rating"`
+	IsOpen       bool          `json:"is_open" db:"is_open"`
+	Verified     bool          `json:"verified" db:"verified"`
+	Version      int           `json:"-" db:"version"`
+	CreatedAt    time.Time     `json:"created_at" db:"created_at"`
+}
+
+type ShopAddress struct {
+	Street     *string `json:"street"`
+	City       *string `json:"city"`
+	Province   *string `json:"province"`
+	PostalCode *string `json:"postal_code"`
+}
+
+type ShopLocation struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+type ShopRating struct {
+	Average float64 `json:"average"`
+	Count   int     `json:"count"`
 }
 
 type DeleteShopRequest struct {
@@ -30,17 +91,117 @@ type DeleteShopRequest struct {
 	Id string `validate:"uuid" db:"id"`
 }
 
+type RestoreShopRequest struct {
+	UserId string `prop:"user_id" validate:"uuid" db:"user_id"`
+
+	Id string `params:"id" validate:"uuid" db:"id"`
+}
+
+type TrashRequest struct {
+	UserId   string `prop:"user_id" validate:"uuid"`
+	Page     int    `query:"page" validate:"required"`
+	Paginate int    `query:"paginate" validate:"required"`
+}
+
+func (r *TrashRequest) SetDefault() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Paginate < 1 {
+		r.Paginate = 10
+	}
+}
+
+type TrashItem struct {
+	Id        string    `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	Slug      string    `json:"slug" db:"slug"`
+	DeletedAt time.Time `json:"deleted_at" db:"deleted_at"`
+}
+
+type TrashResponse struct {
+	Items []TrashItem `json:"items"`
+	Meta  types.Meta  `json:"meta"`
+}
+
+// PurgeRequest hard-deletes the shops soft-deleted before Before that no
+// longer have products.
+type PurgeRequest struct {
+	Before time.Time
+}
+
+type PurgeResponse struct {
+	Ids []string
+	// Files are the verification documents of the purged shops, relative
+	// to the private storage.
+	Files []string
+}
+
+// UpdateShopRequest is a JSON Merge Patch (RFC 7396): only the members
+// present in the body are changed and null clears a nullable column.
 type UpdateShopRequest struct {
 	UserId string `prop:"user_id" validate:"uuid" db:"user_id"`
 
-	Id          string `params:"id" validate:"uuid" db:"id"`
-	Name        string `json:"name" validate:"required" db:"name"`
-	Description string `json:"description" validate:"required" db:"description"`
-	Terms       string `json:"terms" validate:"required" db:"terms"`
+	Id          string               `params:"id" validate:"uuid" db:"id"`
+	Name        types.Patch[string]  `json:"name" validate:"omitempty,min=1" db:"name"`
+	Slug        types.Patch[string]  `json:"slug" validate:"omitempty,min=1,max=100,slug" db:"slug"`
+	Description types.Patch[string]  `json:"description" validate:"omitempty,min=1" db:"description"`
+	Terms       types.Patch[string]  `json:"terms" validate:"omitempty,min=1" db:"terms"`
+	LogoUrl     types.Patch[string]  `json:"logo_url" validate:"omitempty,url" db:"logo_url"`
+	BannerUrl   types.Patch[string]  `json:"banner_url" validate:"omitempty,url" db:"banner_url"`
+	Phone       types.Patch[string]  `json:"phone" validate:"omitempty,e164" db:"phone"`
+	Email       types.Patch[string]  `json:"email" validate:"omitempty,email" db:"email"`
+	Address     types.Patch[string]  `json:"address" validate:"omitempty,max=500" db:"address"`
+	City        types.Patch[string]  `json:"city" validate:"omitempty,max=255" db:"city"`
+	Province    types.Patch[string]  `json:"province" validate:"omitempty,max=255" db:"province"`
+	PostalCode  types.Patch[string]  `json:"postal_code" validate:"omitempty,numeric,max=16" db:"postal_code"`
+	Latitude    types.Patch[float64] `json:"latitude" validate:"omitempty,latitude"`
+	Longitude   types.Patch[float64] `json:"longitude" validate:"omitempty,longitude"`
+
+	// Version is the one sent in If-Match, the update fails when it is stale.
+	Version int `db:"version"`
+}
+
+func (r *UpdateShopRequest) CostumValidation() (int, map[string][]string) {
+	errors := types.NotNull(map[string]interface{ IsNull() bool }{
+		"name":        r.Name,
+		"slug":        r.Slug,
+		"description": r.Description,
+		"terms":       r.Terms,
+	})
+
+	// the coordinate is one point, both halves change (or clear) together
+	if r.Latitude.Set != r.Longitude.Set || r.Latitude.Null != r.Longitude.Null {
+		errors["latitude"] = append(errors["latitude"], "latitude dan longitude harus diubah bersamaan.")
+	}
+
+	if len(errors) > 0 {
+		return 400, errors
+	}
+
+	errors = nil
+	return 0, errors
+}
+
+// Location returns the member to write into the location column: absent
+// when no coordinate is sent, null when both are null.
+func (r *UpdateShopRequest) Location() types.Patch[*types.Point] {
+	if !r.Latitude.Set || !r.Longitude.Set {
+		return types.Patch[*types.Point]{}
+	}
+
+	if r.Latitude.Null || r.Longitude.Null {
+		return types.Patch[*types.Point]{Set: true, Null: true}
+	}
+
+	p := types.NewPoint(r.Latitude.Value, r.Longitude.Value)
+	return types.Patch[*types.Point]{Set: true, Value: &p}
 }
 
 type UpdateShopResponse struct {
-	Id string `json:"id" db:"id"`
+	Id      string `json:"id" db:"id"`
+	Version int    `json:"-" db:"version"`
 }
 
 type ShopsRequest struct {
@@ -62,9 +223,202 @@ func (r *ShopsRequest) SetDefault() {
 type ShopItem struct {
 	Id   string `json:"id" db:"id"`
 	Name string `json:"name" db:"name"`
+	Slug string `json:"slug" db:"slug"`
 }
 
 type ShopsResponse struct {
 	Items []ShopItem `json:"items"`
 	Meta  types.Meta `json:"meta"`
 }
+
+type NearbyShopsRequest struct {
+	LatitudeStr  string  `query:"lat" validate:"required,latitude"`
+	LongitudeStr string  `query:"lng" validate:"required,longitude"`
+	RadiusKm     float64 `query:"radius_km" validate:"gt=0,lte=100"`
+
+	Page     int `query:"page" validate:"required"`
+	Paginate int `query:"paginate" validate:"required"`
+
+	Latitude  float64
+	Longitude float64
+}
+
+func (r *NearbyShopsRequest) SetDefault() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Paginate < 1 {
+		r.Paginate = 10
+	}
+
+	if r.RadiusKm == 0 {
+		r.RadiusKm = 10
+	}
+}
+
+// ParseCoordinate converts the validated lat/lng query strings into numbers.
+func (r *NearbyShopsRequest) ParseCoordinate() {
+	r.Latitude, _ = strconv.ParseFloat(r.LatitudeStr, 64)
+	r.Longitude, _ = strconv.ParseFloat(r.LongitudeStr, 64)
+}
+
+type NearbyShopItem struct {
+	Id         string       `json:"id" db:"id"`
+	Name       string       `json:"name" db:"name"`
+	Slug       string       `json:"slug" db:"slug"`
+	LogoUrl    *string      `json:"logo_url" db:"logo_url"`
+	City       *string      `json:"city" db:"city"`
+	Location   ShopLocation `json:"location"`
+	DistanceKm float64      `json:"distance_km" db:"distance_km"`
+}
+
+type NearbyShopsResponse struct {
+	Items []NearbyShopItem `json:"items"`
+	Meta  types.Meta       `json:"meta"`
+}
+
+// OpeningHour is the time a shop opens on a weekday. A CloseTime before
+// OpenTime closes on the next weekday, ex: 22:00 to 02:00.
+type OpeningHour struct {
+	Weekday   int    `json:"weekday" validate:"gte=0,lte=6" db:"weekday"` // 0 = sunday
+	OpenTime  string `json:"open_time" validate:"required,datetime=15:04" db:"open_time"`
+	CloseTime string `json:"close_time" validate:"required,datetime=15:04" db:"close_time"`
+}
+
+type ShopVacation struct {
+	StartAt time.Time  `json:"start_at" db:"vacation_start_at"`
+	EndAt   *time.Time `json:"end_at" db:"vacation_end_at"`
+	Message *string    `json:"message" db:"vacation_message"`
+}
+
+type GetShopScheduleRequest struct {
+	Id string `params:"id" validate:"uuid" db:"id"`
+}
+
+type ShopScheduleResponse struct {
+	Timezone     string        `json:"timezone" db:"timezone"`
+	IsOpen       bool          `json:"is_open" db:"is_open"`
+	OpeningHours []OpeningHour `json:"opening_hours"`
+	Vacation     *ShopVacation `json:"vacation"`
+}
+
+type UpdateShopScheduleRequest struct {
+	UserId string `prop:"user_id" validate:"uuid" db:"user_id"`
+
+	Id           string        `params:"id" validate:"uuid" db:"id"`
+	Timezone     string        `json:"timezone" validate:"required,timezone" db:"timezone"`
+	OpeningHours []OpeningHour `json:"opening_hours" validate:"max=7,unique=Weekday,dive"`
+}
+
+func (r *UpdateShopScheduleRequest) CostumValidation() (int, map[string][]string) {
+	var errors = make(map[string][]string)
+
+	for i, h := range r.OpeningHours {
+		// a close time before the open time runs past midnight
+		if h.CloseTime == h.OpenTime {
+			field := "opening_hours[" + strconv.Itoa(i) + "].close_time"
+			errors[field] = append(errors[field], "close time tidak boleh sama dengan open time.")
+		}
+	}
+
+	if len(errors) > 0 {
+		return 400, errors
+	}
+
+	return 0, nil
+}
+
+type SetShopVacationRequest struct {
+	UserId string `prop:"user_id" validate:"uuid" db:"user_id"`
+
+	Id      string     `params:"id" validate:"uuid" db:"id"`
+	StartAt time.Time  `json:"start_at" validate:"required" db:"vacation_start_at"`
+	EndAt   *time.Time `json:"end_at" validate:"omitempty,gtfield=StartAt" db:"vacation_end_at"`
+	Message *string    `json:"message" validate:"omitempty,max=255" db:"vacation_message"`
+}
+
+type EndShopVacationRequest struct {
+	UserId string `prop:"user_id" validate:"uuid" db:"user_id"`
+
+	Id string `params:"id" validate:"uuid" db:"id"`
+}
+
+type VerificationStatus string
+
+const (
+	VerificationPending  VerificationStatus = "pending"
+	VerificationApproved VerificationStatus = "approved"
+	VerificationRejected VerificationStatus = "rejected"
+)
+
+// CanTransitionTo reports whether a verification request may move to next.
+// Only pending requests can be reviewed, approved and rejected are final.
+func (s VerificationStatus) CanTransitionTo(next VerificationStatus) bool {
+	return s == VerificationPending && (next == VerificationApproved || next == VerificationRejected)
+}
+
+type CreateShopVerificationRequest struct {
+	UserId string `prop:"user_id" validate:"uuid" db:"user_id"`
+
+	ShopId       string `params:"id" validate:"uuid" db:"shop_id"`
+	KtpDocument  string `json:"ktp_document" validate:"required,base64_file" db:"-"`
+	NpwpDocument string `json:"npwp_document" validate:"required,base64_file" db:"-"`
+
+	KtpFile  string `db:"ktp_file"`
+	NpwpFile string `db:"npwp_file"`
+}
+
+type CreateShopVerificationResponse struct {
+	Id     string             `json:"id" db:"id"`
+	Status VerificationStatus `json:"status" db:"status"`
+}
+
+type GetShopVerificationRequest struct {
+	UserId string `prop:"user_id" validate:"uuid" db:"user_id"`
+
+	ShopId string `params:"id" validate:"uuid" db:"shop_id"`
+}
+
+type ShopVerification struct {
+	Id           string             `json:"id" db:"id"`
+	ShopId       string             `json:"shop_id" db:"shop_id"`
+	ShopName     string             `json:"shop_name" db:"shop_name"`
+	Status       VerificationStatus `json:"status" db:"status"`
+	KtpFile      string             `json:"-" db:"ktp_file"`
+	NpwpFile     string             `json:"-" db:"npwp_file"`
+	KtpUrl       string             `json:"ktp_url" db:"-"`
+	NpwpUrl      string             `json:"npwp_url" db:"-"`
+	RejectReason *string            `json:"reject_reason" db:"reject_reason"`
+	ReviewedAt   *time.Time         `json:"reviewed_at" db:"reviewed_at"`
+	CreatedAt    time.Time          `json:"created_at" db:"created_at"`
+}
+
+type ShopVerificationsRequest struct {
+	Status   string `query:"status" validate:"omitempty,oneof=pending approved rejected"`
+	Page     int    `query:"page" validate:"required"`
+	Paginate int    `query:"paginate" validate:"required"`
+}
+
+func (r *ShopVerificationsRequest) SetDefault() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Paginate < 1 {
+		r.Paginate = 10
+	}
+}
+
+type ShopVerificationsResponse struct {
+	Items []ShopVerification `json:"items"`
+	Meta  types.Meta         `json:"meta"`
+}
+
+type ReviewShopVerificationRequest struct {
+	ReviewerId string `prop:"user_id" validate:"uuid" db:"reviewed_by"`
+
+	Id     string             `params:"verification_id" validate:"uuid" db:"id"`
+	Status VerificationStatus `db:"status"`
+	Reason *string            `json:"reason" validate:"required_if=Status rejected,omitempty,max=500" db:"reject_reason"`
+}
@@ -0,0 +1,64 @@
+package entity
+
+import (
+	"codebase-app/pkg/validator"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateShopRequestRequiredMembers(t *testing.T) {
+	v := validator.NewValidator()
+
+	for _, field := range []string{"name", "slug", "description", "terms"} {
+		tests := []struct {
+			name    string
+			body    string
+			invalid bool
+		}{
+			{"absent", `{}`, false},
+			{"empty", `{"` + field + `":""}`, true},
+			{"null", `{"` + field + `":null}`, true},
+		}
+
+		for _, tt := range tests {
+			t.Run(field+"/"+tt.name, func(t *testing.T) {
+				req := UpdateShopRequest{
+					UserId: "84095313-f3dc-4529-b869-24bb5c77c1a4",
+					Id:     "9aa56858-974a-4c5a-9aa8-0fcce63430f7",
+				}
+				require.NoError(t, json.Unmarshal([]byte(tt.body), &req))
+
+				_, errs := req.CostumValidation()
+				err := v.Validate(&req)
+
+				assert.Equal(t, tt.invalid, err != nil || len(errs) > 0, "validate: %v, costum: %v", err, errs)
+			})
+		}
+	}
+}
+
+func TestUpdateShopScheduleRequestHours(t *testing.T) {
+	tests := []struct {
+		name        string
+		open, close string
+		invalid     bool
+	}{
+		{"same day", "08:00", "17:00", false},
+		{"past midnight", "20:00", "02:00", false},
+		{"empty range", "08:00", "08:00", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := UpdateShopScheduleRequest{
+				OpeningHours: []OpeningHour{{Weekday: 5, OpenTime: tt.open, CloseTime: tt.close}},
+			}
+
+			_, errs := req.CostumValidation()
+			assert.Equal(t, tt.invalid, len(errs) > 0, errs)
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg/openapi"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Operations documents the routes of Register.
+var Operations = []openapi.Operation{
+	{
+		Handler:  (*shopHandler).GetShopVerifications,
+		Summary:  "List shop verification requests, as an admin",
+		Request:  entity.ShopVerificationsRequest{},
+		Response: entity.ShopVerificationsResponse{},
+	},
+	{
+		Handler: (*shopHandler).ApproveShopVerification,
+		Summary: "Approve a shop verification request, as an admin",
+		Request: entity.ReviewShopVerificationRequest{},
+	},
+	{
+		Handler: (*shopHandler).RejectShopVerification,
+		Summary: "Reject a shop verification request, as an admin",
+		Request: entity.ReviewShopVerificationRequest{},
+	},
+	{
+		Handler:  (*shopHandler).GetShops,
+		Summary:  "List your shops",
+		Request:  entity.ShopsRequest{},
+		Response: entity.ShopsResponse{},
+	},
+	{
+		Handler:  (*shopHandler).CreateShop,
+		Summary:  "Create a shop",
+		Request:  entity.CreateShopRequest{},
+		Response: entity.CreateShopResponse{},
+		Status:   fiber.StatusCreated,
+	},
+	{
+		Handler:  (*shopHandler).GetNearbyShops,
+		Summary:  "List shops near a location",
+		Request:  entity.NearbyShopsRequest{},
+		Response: entity.NearbyShopsResponse{},
+	},
+	{
+		Handler:  (*shopHandler).GetTrash,
+		Summary:  "List your deleted shops",
+		Request:  entity.TrashRequest{},
+		Response: entity.TrashResponse{},
+	},
+	{
+		Handler:     (*shopHandler).GetShopBySlug,
+		Summary:     "Get a shop by its slug",
+		Description: "Answers 304 when If-None-Match matches the ETag.",
+		Request:     entity.GetShopBySlugRequest{},
+		Response:    entity.GetShopResponse{},
+	},
+	{
+		Handler:     (*shopHandler).GetShop,
+		Summary:     "Get a shop",
+		Description: "Answers 304 when If-None-Match matches the ETag.",
+		Request:     entity.GetShopRequest{},
+		Response:    entity.GetShopResponse{},
+	},
+	{
+		Handler: (*shopHandler).DeleteShop,
+		Summary: "Delete a shop and its products",
+		Request: entity.DeleteShopRequest{},
+	},
+	{
+		Handler: (*shopHandler).RestoreShop,
+		Summary: "Restore a deleted shop",
+		Request: entity.RestoreShopRequest{},
+	},
+	{
+		Handler:  (*shopHandler).UpdateShop,
+		Summary:  "Update a shop",
+		Request:  entity.UpdateShopRequest{},
+		Response: entity.UpdateShopResponse{},
+	},
+	{
+		Handler:  (*shopHandler).GetShopSchedule,
+		Summary:  "Get the opening hours of a shop",
+		Request:  entity.GetShopScheduleRequest{},
+		Response: entity.ShopScheduleResponse{},
+	},
+	{
+		Handler: (*shopHandler).UpdateShopSchedule,
+		Summary: "Set the opening hours of a shop",
+		Request: entity.UpdateShopScheduleRequest{},
+	},
+	{
+		Handler: (*shopHandler).SetShopVacation,
+		Summary: "Close a shop for a vacation",
+		Request: entity.SetShopVacationRequest{},
+	},
+	{
+		Handler: (*shopHandler).EndShopVacation,
+		Summary: "End the vacation of a shop",
+		Request: entity.EndShopVacationRequest{},
+	},
+	{
+		Handler:  (*shopHandler).GetShopVerification,
+		Summary:  "Get the verification request of a shop",
+		Request:  entity.GetShopVerificationRequest{},
+		Response: entity.ShopVerification{},
+	},
+	{
+		Handler:  (*shopHandler).CreateShopVerification,
+		Summary:  "Request verification of a shop",
+		Request:  entity.CreateShopVerificationRequest{},
+		Response: entity.CreateShopVerificationResponse{},
+		Status:   fiber.StatusCreated,
+	},
+}
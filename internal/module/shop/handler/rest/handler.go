@@ -2,11 +2,13 @@ package handler
 
 import (
 	"codebase-app/internal/adapter"
+	integration "codebase-app/internal/integration/localstorage"
 	"codebase-app/internal/middleware"
 	"codebase-app/internal/module/shop/entity"
 	"codebase-app/internal/module/shop/ports"
 	"codebase-app/internal/module/shop/repository"
 	"codebase-app/internal/module/shop/service"
+	"codebase-app/pkg"
 	"codebase-app/pkg/errmsg"
 	"codebase-app/pkg/response"
 
@@ -22,7 +24,8 @@ func NewShopHandler() *shopHandler {
 	var (
 		handler = new(shopHandler)
 		repo    = repository.NewShopRepository(adapter.Adapters.ShopeefunPostgres)
-		service = service.NewShopService(repo)
+		storage = integration.NewLocalStorageIntegration()
+		service = service.NewShopService(repo, storage)
 	)
 	handler.service = service
 
@@ -30,30 +33,46 @@ func NewShopHandler() *shopHandler {
 }
 
 func (h *shopHandler) Register(router fiber.Router) {
+	adminOnly := middleware.AuthRole([]string{"admin"})
+
+	router.Get("/shops/verifications", middleware.AuthBearer, adminOnly, h.GetShopVerifications)
+	router.Post("/shops/verifications/:verification_id/approve", middleware.AuthBearer, adminOnly, h.ApproveShopVerification)
+	router.Post("/shops/verifications/:verification_id/reject", middleware.AuthBearer, adminOnly, h.RejectShopVerification)
+
 	router.Get("/shops", middleware.UserIdHeader, h.GetShops)
-	router.Post("/shops", middleware.UserIdHeader, h.CreateShop)
+	router.Post("/shops", middleware.UserIdHeader, middleware.Idempotency(), h.CreateShop)
+	router.Get("/shops/nearby", h.GetNearbyShops)
+	router.Get("/shops/trash", middleware.UserIdHeader, h.GetTrash)
+	router.Get("/shops/by-slug/:slug", h.GetShopBySlug)
 	router.Get("/shops/:id", h.GetShop)
 	router.Delete("/shops/:id", middleware.UserIdHeader, h.DeleteShop)
-	router.Patch("/shops/:id", middleware.UserIdHeader, h.UpdateShop)
+	router.Post("/shops/:id/restore", middleware.UserIdHeader, h.RestoreShop)
+	router.Patch("/shops/:id", middleware.UserIdHeader, middleware.IfMatch, h.UpdateShop)
+	router.Get("/shops/:id/schedule", h.GetShopSchedule)
+	router.Put("/shops/:id/schedule", middleware.UserIdHeader, h.UpdateShopSchedule)
+	router.Put("/shops/:id/vacation", middleware.UserIdHeader, h.SetShopVacation)
+	router.Delete("/shops/:id/vacation", middleware.UserIdHeader, h.EndShopVacation)
+	router.Get("/shops/:id/verification", middleware.UserIdHeader, h.GetShopVerification)
+	router.Post("/shops/:id/verification", middleware.UserIdHeader, h.CreateShopVerification)
 }
 
 func (h *shopHandler) CreateShop(c *fiber.Ctx) error {
 	var (
 		req = new(entity.CreateShopRequest)
-		ctx = c.Context()
+		ctx = c.UserContext()
 		v   = adapter.Adapters.Validator
 		l   = middleware.GetLocals(c)
 	)
 
 	if err := c.BodyParser(req); err != nil {
-		log.Warn().Err(err).Msg("handler::CreateShop - Parse request body")
+		log.Ctx(ctx).Warn().Err(err).Msg("handler::CreateShop - Parse request body")
 		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
 	}
 
 	req.UserId = l.UserId
 
 	if err := v.Validate(req); err != nil {
-		log.Warn().Err(err).Any("payload", req).Msg("handler::CreateShop - Validate request body")
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::CreateShop - Validate request body")
 		code, errs := errmsg.Errors(err, req)
 		return c.Status(code).JSON(response.Error(errs))
 	}
@@ -71,14 +90,14 @@ func (h *shopHandler) CreateShop(c *fiber.Ctx) error {
 func (h *shopHandler) GetShop(c *fiber.Ctx) error {
 	var (
 		req = new(entity.GetShopRequest)
-		ctx = c.Context()
+		ctx = c.UserContext()
 		v   = adapter.Adapters.Validator
 	)
 
 	req.Id = c.Params("id")
 
 	if err := v.Validate(req); err != nil {
-		log.Warn().Err(err).Any("payload", req).Msg("handler::GetShop - Validate request body")
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::GetShop - Validate request body")
 		code, errs := errmsg.Errors(err, req)
 		return c.Status(code).JSON(response.Error(errs))
 	}
@@ -89,13 +108,37 @@ func (h *shopHandler) GetShop(c *fiber.Ctx) error {
 		return c.Status(code).JSON(response.Error(errs))
 	}
 
-	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+	return middleware.SendETagged(c, resp.Version, response.Success(resp, ""))
+}
+
+func (h *shopHandler) GetShopBySlug(c *fiber.Ctx) error {
+	var (
+		req = new(entity.GetShopBySlugRequest)
+		ctx = c.UserContext()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.Slug = c.Params("slug")
+
+	if err := v.Validate(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::GetShopBySlug - Validate request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetShopBySlug(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return middleware.SendETagged(c, resp.Version, response.Success(resp, ""))
 }
 
 func (h *shopHandler) DeleteShop(c *fiber.Ctx) error {
 	var (
 		req = new(entity.DeleteShopRequest)
-		ctx = c.Context()
+		ctx = c.UserContext()
 		v   = adapter.Adapters.Validator
 		l   = middleware.GetLocals(c)
 	)
@@ -103,7 +146,7 @@ func (h *shopHandler) DeleteShop(c *fiber.Ctx) error {
 	req.Id = c.Params("id")
 
 	if err := v.Validate(req); err != nil {
-		log.Warn().Err(err).Any("payload", req).Msg("handler::DeleteShop - Validate request body")
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::DeleteShop - Validate request body")
 		code, errs := errmsg.Errors(err, req)
 		return c.Status(code).JSON(response.Error(errs))
 	}
@@ -120,44 +163,51 @@ func (h *shopHandler) DeleteShop(c *fiber.Ctx) error {
 func (h *shopHandler) UpdateShop(c *fiber.Ctx) error {
 	var (
 		req = new(entity.UpdateShopRequest)
-		ctx = c.Context()
+		ctx = c.UserContext()
 		v   = adapter.Adapters.Validator
 		l   = middleware.GetLocals(c)
 	)
 
 	if err := c.BodyParser(req); err != nil {
-		log.Warn().Err(err).Msg("handler::UpdateShop - Parse request body")
+		log.Ctx(ctx).Warn().Err(err).Msg("handler::UpdateShop - Parse request body")
 		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
 	}
 
 	req.UserId = l.UserId
 	req.Id = c.Params("id")
+	req.Version = l.IfMatch
 
 	if err := v.Validate(req); err != nil {
-		log.Warn().Err(err).Any("payload", req).Msg("handler::UpdateShop - Validate request body")
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::UpdateShop - Validate request body")
 		code, errs := errmsg.Errors(err, req)
 		return c.Status(code).JSON(response.Error(errs))
 	}
 
+	if code, errs := req.CostumValidation(); code != 0 {
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
 	resp, err := h.service.UpdateShop(ctx, req)
 	if err != nil {
 		code, errs := errmsg.Errors[error](err)
 		return c.Status(code).JSON(response.Error(errs))
 	}
 
+	c.Set(fiber.HeaderETag, pkg.FormatETag(resp.Version))
+
 	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
 }
 
 func (h *shopHandler) GetShops(c *fiber.Ctx) error {
 	var (
 		req = new(entity.ShopsRequest)
-		ctx = c.Context()
+		ctx = c.UserContext()
 		v   = adapter.Adapters.Validator
 		l   = middleware.GetLocals(c)
 	)
 
 	if err := c.QueryParser(req); err != nil {
-		log.Warn().Err(err).Msg("handler::GetShops - Parse request query")
+		log.Ctx(ctx).Warn().Err(err).Msg("handler::GetShops - Parse request query")
 		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
 	}
 
@@ -165,7 +215,7 @@ func (h *shopHandler) GetShops(c *fiber.Ctx) error {
 	req.SetDefault()
 
 	if err := v.Validate(req); err != nil {
-		log.Warn().Err(err).Any("payload", req).Msg("handler::GetShops - Validate request body")
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::GetShops - Validate request body")
 		code, errs := errmsg.Errors(err, req)
 		return c.Status(code).JSON(response.Error(errs))
 	}
@@ -179,3 +229,334 @@ func (h *shopHandler) GetShops(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
 
 }
+
+func (h *shopHandler) GetNearbyShops(c *fiber.Ctx) error {
+	var (
+		req = new(entity.NearbyShopsRequest)
+		ctx = c.UserContext()
+		v   = adapter.Adapters.Validator
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("handler::GetNearbyShops - Parse request query")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.SetDefault()
+
+	if err := v.Validate(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::GetNearbyShops - Validate request query")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	req.ParseCoordinate()
+
+	resp, err := h.service.GetNearbyShops(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *shopHandler) GetShopSchedule(c *fiber.Ctx) error {
+	var (
+		req = new(entity.GetShopScheduleRequest)
+		ctx = c.UserContext()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.Id = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::GetShopSchedule - Validate request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetShopSchedule(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *shopHandler) UpdateShopSchedule(c *fiber.Ctx) error {
+	var (
+		req = new(entity.UpdateShopScheduleRequest)
+		ctx = c.UserContext()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("handler::UpdateShopSchedule - Parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.UserId
+	req.Id = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::UpdateShopSchedule - Validate request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if code, errs := req.CostumValidation(); code != 0 {
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if err := h.service.UpdateShopSchedule(ctx, req); err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(nil, ""))
+}
+
+func (h *shopHandler) SetShopVacation(c *fiber.Ctx) error {
+	var (
+		req = new(entity.SetShopVacationRequest)
+		ctx = c.UserContext()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("handler::SetShopVacation - Parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.UserId
+	req.Id = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::SetShopVacation - Validate request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if err := h.service.SetShopVacation(ctx, req); err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(nil, ""))
+}
+
+func (h *shopHandler) EndShopVacation(c *fiber.Ctx) error {
+	var (
+		req = new(entity.EndShopVacationRequest)
+		ctx = c.UserContext()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	req.UserId = l.UserId
+	req.Id = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::EndShopVacation - Validate request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if err := h.service.EndShopVacation(ctx, req); err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(nil, ""))
+}
+
+func (h *shopHandler) CreateShopVerification(c *fiber.Ctx) error {
+	var (
+		req = new(entity.CreateShopVerificationRequest)
+		ctx = c.UserContext()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("handler::CreateShopVerification - Parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.UserId
+	req.ShopId = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		// documents are huge base64 strings, keep them out of the logs
+		log.Ctx(ctx).Warn().Err(err).Str("shop_id", req.ShopId).Msg("handler::CreateShopVerification - Validate request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.CreateShopVerification(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response.Success(resp, ""))
+}
+
+func (h *shopHandler) GetShopVerification(c *fiber.Ctx) error {
+	var (
+		req = new(entity.GetShopVerificationRequest)
+		ctx = c.UserContext()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	req.UserId = l.UserId
+	req.ShopId = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::GetShopVerification - Validate request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetShopVerification(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *shopHandler) GetShopVerifications(c *fiber.Ctx) error {
+	var (
+		req = new(entity.ShopVerificationsRequest)
+		ctx = c.UserContext()
+		v   = adapter.Adapters.Validator
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("handler::GetShopVerifications - Parse request query")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.SetDefault()
+
+	if err := v.Validate(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::GetShopVerifications - Validate request query")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetShopVerifications(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *shopHandler) ApproveShopVerification(c *fiber.Ctx) error {
+	return h.reviewShopVerification(c, entity.VerificationApproved)
+}
+
+func (h *shopHandler) RejectShopVerification(c *fiber.Ctx) error {
+	return h.reviewShopVerification(c, entity.VerificationRejected)
+}
+
+func (h *shopHandler) reviewShopVerification(c *fiber.Ctx, status entity.VerificationStatus) error {
+	var (
+		req = new(entity.ReviewShopVerificationRequest)
+		ctx = c.UserContext()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(req); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msg("handler::ReviewShopVerification - Parse request body")
+			return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+		}
+	}
+
+	req.ReviewerId = l.UserId
+	req.Id = c.Params("verification_id")
+	req.Status = status
+	if status == entity.VerificationApproved {
+		req.Reason = nil
+	}
+
+	if err := v.Validate(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::ReviewShopVerification - Validate request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if err := h.service.ReviewShopVerification(ctx, req); err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(nil, ""))
+}
+
+func (h *shopHandler) GetTrash(c *fiber.Ctx) error {
+	var (
+		req = new(entity.TrashRequest)
+		ctx = c.UserContext()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("handler::GetTrash - Parse request query")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.UserId
+	req.SetDefault()
+
+	if err := v.Validate(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::GetTrash - Validate request query")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetTrash(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *shopHandler) RestoreShop(c *fiber.Ctx) error {
+	var (
+		req = new(entity.RestoreShopRequest)
+		ctx = c.UserContext()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	req.UserId = l.UserId
+	req.Id = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("handler::RestoreShop - Validate request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if err := h.service.RestoreShop(ctx, req); err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(nil, ""))
+}
@@ -8,15 +8,45 @@ import (
 type ShopRepository interface {
 	CreateShop(ctx context.Context, req *entity.CreateShopRequest) (*entity.CreateShopResponse, error)
 	GetShop(ctx context.Context, req *entity.GetShopRequest) (*entity.GetShopResponse, error)
+	GetShopBySlug(ctx context.Context, req *entity.GetShopBySlugRequest) (*entity.GetShopResponse, error)
 	DeleteShop(ctx context.Context, req *entity.DeleteShopRequest) error
 	UpdateShop(ctx context.Context, req *entity.UpdateShopRequest) (*entity.UpdateShopResponse, error)
 	GetShops(ctx context.Context, req *entity.ShopsRequest) (*entity.ShopsResponse, error)
+	GetNearbyShops(ctx context.Context, req *entity.NearbyShopsRequest) (*entity.NearbyShopsResponse, error)
+	GetShopSchedule(ctx context.Context, req *entity.GetShopScheduleRequest) (*entity.ShopScheduleResponse, error)
+	UpdateShopSchedule(ctx context.Context, req *entity.UpdateShopScheduleRequest) error
+	SetShopVacation(ctx context.Context, req *entity.SetShopVacationRequest) error
+	EndShopVacation(ctx context.Context, req *entity.EndShopVacationRequest) error
+	// IsShopOpen is the guard stock reservations call before holding stock,
+	// it fails with 404 for an unknown shop.
+	IsShopOpen(ctx context.Context, shopId string) (bool, error)
+	CreateShopVerification(ctx context.Context, req *entity.CreateShopVerificationRequest) (*entity.CreateShopVerificationResponse, error)
+	GetShopVerification(ctx context.Context, req *entity.GetShopVerificationRequest) (*entity.ShopVerification, error)
+	GetShopVerifications(ctx context.Context, req *entity.ShopVerificationsRequest) (*entity.ShopVerificationsResponse, error)
+	ReviewShopVerification(ctx context.Context, req *entity.ReviewShopVerificationRequest) error
+	GetTrash(ctx context.Context, req *entity.TrashRequest) (*entity.TrashResponse, error)
+	RestoreShop(ctx context.Context, req *entity.RestoreShopRequest) error
+	PurgeShops(ctx context.Context, req *entity.PurgeRequest) (*entity.PurgeResponse, error)
 }
 
 type ShopService interface {
 	CreateShop(ctx context.Context, req *entity.CreateShopRequest) (*entity.CreateShopResponse, error)
 	GetShop(ctx context.Context, req *entity.GetShopRequest) (*entity.GetShopResponse, error)
+	GetShopBySlug(ctx context.Context, req *entity.GetShopBySlugRequest) (*entity.GetShopResponse, error)
 	DeleteShop(ctx context.Context, req *entity.DeleteShopRequest) error
 	UpdateShop(ctx context.Context, req *entity.UpdateShopRequest) (*entity.UpdateShopResponse, error)
 	GetShops(ctx context.Context, req *entity.ShopsRequest) (*entity.ShopsResponse, error)
+	GetNearbyShops(ctx context.Context, req *entity.NearbyShopsRequest) (*entity.NearbyShopsResponse, error)
+	GetShopSchedule(ctx context.Context, req *entity.GetShopScheduleRequest) (*entity.ShopScheduleResponse, error)
+	UpdateShopSchedule(ctx context.Context, req *entity.UpdateShopScheduleRequest) error
+	SetShopVacation(ctx context.Context, req *entity.SetShopVacationRequest) error
+	EndShopVacation(ctx context.Context, req *entity.EndShopVacationRequest) error
+	IsShopOpen(ctx context.Context, shopId string) (bool, error)
+	CreateShopVerification(ctx context.Context, req *entity.CreateShopVerificationRequest) (*entity.CreateShopVerificationResponse, error)
+	GetShopVerification(ctx context.Context, req *entity.GetShopVerificationRequest) (*entity.ShopVerification, error)
+	GetShopVerifications(ctx context.Context, req *entity.ShopVerificationsRequest) (*entity.ShopVerificationsResponse, error)
+	ReviewShopVerification(ctx context.Context, req *entity.ReviewShopVerificationRequest) error
+	GetTrash(ctx context.Context, req *entity.TrashRequest) (*entity.TrashResponse, error)
+	RestoreShop(ctx context.Context, req *entity.RestoreShopRequest) error
+	PurgeShops(ctx context.Context, req *entity.PurgeRequest) (int64, error)
 }
@@ -3,21 +3,27 @@ package repository
 import (
 	"codebase-app/internal/module/shop/entity"
 	"codebase-app/internal/module/shop/ports"
+	"codebase-app/pkg/database"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/types"
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/rs/zerolog/log"
 )
 
 var _ ports.ShopRepository = &shopRepository{}
 
 type shopRepository struct {
-	db *sqlx.DB
+	db *database.DB
 }
 
 func NewShopRepository(db *sqlx.DB) *shopRepository {
 	return &shopRepository{
-		db: db,
+		db: database.New(db),
 	}
 }
 
@@ -25,51 +31,231 @@ func (r *shopRepository) CreateShop(ctx context.Context, req *entity.CreateShopR
 	var resp = new(entity.CreateShopResponse)
 	// Your code here
 	query := `
-		INSERT INTO shops (user_id, name, description, terms)
-		VALUES (?, ?, ?, ?) RETURNING id
+		INSERT INTO shops (
+			user_id, name, slug, description, terms, logo_url, banner_url,
+			phone, email, address, city, province, postal_code, location
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id, slug
 	`
 
 	err := r.db.QueryRowContext(ctx, r.db.Rebind(query),
 		req.UserId,
 		req.Name,
+		req.Slug,
 		req.Description,
-		req.Terms).Scan(&resp.Id)
+		req.Terms,
+		req.LogoUrl,
+		req.BannerUrl,
+		req.Phone,
+		req.Email,
+		req.Address,
+		req.City,
+		req.Province,
+		req.PostalCode,
+		req.Location()).Scan(&resp.Id, &resp.Slug)
 	if err != nil {
-		log.Error().Err(err).Any("payload", req).Msg("repository::CreateShop - Failed to create shop")
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::CreateShop - Failed to create shop")
 		return nil, err
 	}
 
 	return resp, nil
 }
 
-func (r *shopRepository) GetShop(ctx context.Context, req *entity.GetShopRequest) (*entity.GetShopResponse, error) {
-	var resp = new(entity.GetShopResponse)
-	// Your code here
+// shopDao is the flat row of a storefront profile, see getShop.
+type shopDao struct {
+	Id            string       `db:"id"`
+	Name          string       `db:"name"`
+	Slug          string       `db:"slug"`
+	Description   string       `db:"description"`
+	Terms         string       `db:"terms"`
+	LogoUrl       *string      `db:"logo_url"`
+	BannerUrl     *string      `db:"banner_url"`
+	Phone         *string      `db:"phone"`
+	Email         *string      `db:"email"`
+	Address       *string      `db:"address"`
+	City          *string      `db:"city"`
+	Province      *string      `db:"province"`
+	PostalCode    *string      `db:"postal_code"`
+	Location      *types.Point `db:"location"`
+	ProductCount  int          `db:"product_count"`
+	IsOpen        bool         `db:"is_open"`
+	Verified      bool         `db:"verified"`
+	RatingCount   int          `db:"rating_count"`
+	RatingAverage float64      `db:"rating_average"`
+	Version       int          `db:"version"`
+	CreatedAt     time.Time    `db:"created_at"`
+}
+
+func (d *shopDao) toResponse() *entity.GetShopResponse {
+	resp := &entity.GetShopResponse{
+		Id:          d.Id,
+		Name:        d.Name,
+		Slug:        d.Slug,
+		Description: d.Description,
+		Terms:       d.Terms,
+		LogoUrl:     d.LogoUrl,
+		BannerUrl:   d.BannerUrl,
+		Phone:       d.Phone,
+		Email:       d.Email,
+		Address: entity.ShopAddress{
+			Street:     d.Address,
+			City:       d.City,
+			Province:   d.Province,
+			PostalCode: d.PostalCode,
+		},
+		ProductCount: d.ProductCount,
+		IsOpen:       d.IsOpen,
+		Verified:     d.Verified,
+		Rating: entity.ShopRating{
+			Average: d.RatingAverage,
+			Count:   d.RatingCount,
+		},
+		Version:   d.Version,
+		CreatedAt: d.CreatedAt,
+	}
+
+	if d.Location != nil {
+		resp.Location = &entity.ShopLocation{
+			Latitude:  d.Location.Lat(),
+			Longitude: d.Location.Lng(),
+		}
+	}
+
+	return resp
+}
+
+// updateMissError tells a missing shop apart from a stale If-Match version
+// after an optimistic update matched no row.
+func (r *shopRepository) updateMissError(ctx context.Context, req *entity.UpdateShopRequest) error {
+	var exists bool
+
+	query := `SELECT EXISTS (SELECT 1 FROM shops WHERE id = ? AND user_id = ? AND deleted_at IS NULL)`
+
+	err := r.db.GetContext(ctx, &exists, r.db.Rebind(query), req.Id, req.UserId)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::updateMissError - Failed to check shop")
+		return err
+	}
+
+	if !exists {
+		log.Ctx(ctx).Warn().Any("payload", req).Msg("repository::UpdateShop - Shop not found")
+		return errmsg.NewCustomErrors(404, errmsg.WithMessage("Toko tidak ditemukan"))
+	}
+
+	log.Ctx(ctx).Warn().Any("payload", req).Msg("repository::UpdateShop - Version mismatch")
+	return errmsg.NewCustomErrors(412, errmsg.WithMessage("Toko telah diubah oleh pengguna lain, muat ulang lalu coba lagi"))
+}
+
+// getShop returns the storefront profile of the shop matching the given condition.
+func (r *shopRepository) getShop(ctx context.Context, condition string, arg any) (*entity.GetShopResponse, error) {
+	var data = new(shopDao)
+
 	query := `
-		SELECT name, description, terms
-		FROM shops
-		WHERE id = ?
-	`
+		SELECT
+			s.id,
+			s.name,
+			s.slug,
+			s.description,
+			s.terms,
+			s.logo_url,
+			s.banner_url,
+			s.phone,
+			s.email,
+			s.address,
+			s.city,
+			s.province,
+			s.postal_code,
+			s.location,
+			s.version,
+			s.created_at,
+			shop_is_open(s.id) AS is_open,
+			s.verified_at IS NOT NULL AS verified,
+			(
+				SELECT COUNT(*)
+				FROM products p
+				WHERE p.shop_id = s.id AND p.deleted_at IS NULL AND p.status = 'active'
+			) AS product_count,
+			COALESCE(r.rating_count, 0) AS rating_count,
+			COALESCE(ROUND(r.rating_sum::NUMERIC / NULLIF(r.rating_count, 0), 2), 0) AS rating_average
+		FROM shops s
+		LEFT JOIN LATERAL (
+			SELECT SUM(rs.rating_count) AS rating_count, SUM(rs.rating_sum) AS rating_sum
+			FROM product_rating_summaries rs
+			JOIN products p ON p.id = rs.product_id
+			WHERE p.shop_id = s.id AND p.deleted_at IS NULL
+		) r ON TRUE
+		WHERE
+			s.deleted_at IS NULL
+			AND ` + condition
 
-	err := r.db.QueryRowxContext(ctx, r.db.Rebind(query), req.Id).StructScan(resp)
+	err := r.db.QueryRowxContext(ctx, r.db.Rebind(query), arg).StructScan(data)
 	if err != nil {
-		log.Error().Err(err).Any("payload", req).Msg("repository::GetShop - Failed to get shop")
+		if err == sql.ErrNoRows {
+			log.Ctx(ctx).Warn().Err(err).Any("payload", arg).Msg("repository::getShop - Shop not found")
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Toko tidak ditemukan"))
+		}
+		log.Ctx(ctx).Error().Err(err).Any("payload", arg).Msg("repository::getShop - Failed to get shop")
 		return nil, err
 	}
 
-	return resp, nil
+	return data.toResponse(), nil
+}
+
+func (r *shopRepository) GetShop(ctx context.Context, req *entity.GetShopRequest) (*entity.GetShopResponse, error) {
+	return r.getShop(ctx, "s.id = ?", req.Id)
+}
+
+func (r *shopRepository) GetShopBySlug(ctx context.Context, req *entity.GetShopBySlugRequest) (*entity.GetShopResponse, error) {
+	return r.getShop(ctx, "s.slug = ?", req.Slug)
 }
 
-func (r *shopRepository) DeleteShop(ctx context.Context, req *entity.DeleteShopRequest) error {
+// DeleteShop soft-deletes the shop and, with the same deleted_at, every
+// product it still sells, so RestoreShop can bring exactly those back.
+func (r *shopRepository) DeleteShop(ctx context.Context, req *entity.DeleteShopRequest) (err error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::DeleteShop - Failed to begin transaction")
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				log.Ctx(ctx).Error().Err(errRollback).Any("payload", req).Msg("repository::DeleteShop - Failed to rollback transaction")
+			}
+		}
+	}()
+
 	query := `
 		UPDATE shops
 		SET deleted_at = NOW()
-		WHERE id = ? AND user_id = ?
+		WHERE id = ? AND user_id = ? AND deleted_at IS NULL
+		RETURNING deleted_at
 	`
 
-	_, err := r.db.ExecContext(ctx, r.db.Rebind(query), req.Id, req.UserId)
+	var deletedAt time.Time
+	err = tx.QueryRowxContext(ctx, tx.Rebind(query), req.Id, req.UserId).Scan(&deletedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Ctx(ctx).Warn().Any("payload", req).Msg("repository::DeleteShop - Shop not found")
+			err = errmsg.NewCustomErrors(404, errmsg.WithMessage("Toko tidak ditemukan"))
+			return err
+		}
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::DeleteShop - Failed to delete shop")
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(`
+		UPDATE products
+		SET deleted_at = ?
+		WHERE shop_id = ? AND deleted_at IS NULL
+	`), deletedAt, req.Id)
 	if err != nil {
-		log.Error().Err(err).Any("payload", req).Msg("repository::DeleteShop - Failed to delete shop")
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::DeleteShop - Failed to delete shop products")
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::DeleteShop - Failed to commit transaction")
 		return err
 	}
 
@@ -77,23 +263,46 @@ func (r *shopRepository) DeleteShop(ctx context.Context, req *entity.DeleteShopR
 }
 
 func (r *shopRepository) UpdateShop(ctx context.Context, req *entity.UpdateShopRequest) (*entity.UpdateShopResponse, error) {
-	var resp = new(entity.UpdateShopResponse)
+	var (
+		resp = new(entity.UpdateShopResponse)
+		set  = new(types.PatchSet)
+	)
+
+	// only the members present in the merge patch are written
+	types.AddPatch(set, "name", req.Name)
+	types.AddPatch(set, "slug", req.Slug)
+	types.AddPatch(set, "description", req.Description)
+	types.AddPatch(set, "terms", req.Terms)
+	types.AddPatch(set, "logo_url", req.LogoUrl)
+	types.AddPatch(set, "banner_url", req.BannerUrl)
+	types.AddPatch(set, "phone", req.Phone)
+	types.AddPatch(set, "email", req.Email)
+	types.AddPatch(set, "address", req.Address)
+	types.AddPatch(set, "city", req.City)
+	types.AddPatch(set, "province", req.Province)
+	types.AddPatch(set, "postal_code", req.PostalCode)
+	types.AddPatch(set, "location", req.Location())
+
+	if set.Empty() {
+		log.Ctx(ctx).Warn().Any("payload", req).Msg("repository::UpdateShop - Empty patch")
+		return nil, errmsg.NewCustomErrors(400, errmsg.WithMessage("Tidak ada data yang diubah"))
+	}
 
 	query := `
 		UPDATE shops
-		SET name = ?, description = ?, terms = ?, updated_at = NOW()
-		WHERE id = ? AND user_id = ?
-		RETURNING id
+		SET ` + set.Clause() + `, updated_at = NOW()
+		WHERE id = ? AND user_id = ? AND deleted_at IS NULL AND version = ?
+		RETURNING id, version
 	`
 
-	err := r.db.QueryRowxContext(ctx, r.db.Rebind(query),
-		req.Name,
-		req.Description,
-		req.Terms,
-		req.Id,
-		req.UserId).Scan(&resp.Id)
+	args := append(set.Args(), req.Id, req.UserId, req.Version)
+
+	err := r.db.QueryRowxContext(ctx, r.db.Rebind(query), args...).Scan(&resp.Id, &resp.Version)
 	if err != nil {
-		log.Error().Err(err).Any("payload", req).Msg("repository::UpdateShop - Failed to update shop")
+		if err == sql.ErrNoRows {
+			return nil, r.updateMissError(ctx, req)
+		}
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::UpdateShop - Failed to update shop")
 		return nil, err
 	}
 
@@ -116,7 +325,8 @@ func (r *shopRepository) GetShops(ctx context.Context, req *entity.ShopsRequest)
 		SELECT
 			COUNT(id) OVER() as total_data,
 			id,
-			name
+			name,
+			slug
 		FROM shops
 		WHERE
 			deleted_at IS NULL
@@ -130,7 +340,7 @@ func (r *shopRepository) GetShops(ctx context.Context, req *entity.ShopsRequest)
 		req.Paginate*(req.Page-1),
 	)
 	if err != nil {
-		log.Error().Err(err).Any("payload", req).Msg("repository::GetShops - Failed to get shops")
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::GetShops - Failed to get shops")
 		return nil, err
 	}
 
@@ -146,3 +356,694 @@ func (r *shopRepository) GetShops(ctx context.Context, req *entity.ShopsRequest)
 
 	return resp, nil
 }
+
+// nearbyShopsQuery is compiled by sqlx, which turns "::" into ":", so its
+// casts are written with CAST.
+const nearbyShopsQuery = `
+	SELECT
+		COUNT(id) OVER() as total_data,
+		id,
+		name,
+		slug,
+		logo_url,
+		city,
+		location,
+		ST_Distance(location, CAST(ST_MakePoint(:lng, :lat) AS geography)) / 1000 AS distance_km
+	FROM shops
+	WHERE
+		deleted_at IS NULL
+		AND location IS NOT NULL
+		AND ST_DWithin(location, CAST(ST_MakePoint(:lng, :lat) AS geography), :radius_m)
+	ORDER BY distance_km ASC
+	LIMIT :paginate OFFSET :offset
+`
+
+func (r *shopRepository) GetNearbyShops(ctx context.Context, req *entity.NearbyShopsRequest) (*entity.NearbyShopsResponse, error) {
+	type dao struct {
+		TotalData  int         `db:"total_data"`
+		Id         string      `db:"id"`
+		Name       string      `db:"name"`
+		Slug       string      `db:"slug"`
+		LogoUrl    *string     `db:"logo_url"`
+		City       *string     `db:"city"`
+		Location   types.Point `db:"location"`
+		DistanceKm float64     `db:"distance_km"`
+	}
+
+	var (
+		resp = new(entity.NearbyShopsResponse)
+		data = make([]dao, 0, req.Paginate)
+	)
+	resp.Items = make([]entity.NearbyShopItem, 0, req.Paginate)
+
+	arg := map[string]any{
+		"lat":      req.Latitude,
+		"lng":      req.Longitude,
+		"radius_m": req.RadiusKm * 1000,
+		"paginate": req.Paginate,
+		"offset":   req.Paginate * (req.Page - 1),
+	}
+
+	if err := r.db.SelectNamedContext(ctx, &data, nearbyShopsQuery, arg); err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::GetNearbyShops - Failed to get nearby shops")
+		return nil, err
+	}
+
+	if len(data) > 0 {
+		resp.Meta.TotalData = data[0].TotalData
+	}
+
+	for _, d := range data {
+		resp.Items = append(resp.Items, entity.NearbyShopItem{
+			Id:      d.Id,
+			Name:    d.Name,
+			Slug:    d.Slug,
+			LogoUrl: d.LogoUrl,
+			City:    d.City,
+			Location: entity.ShopLocation{
+				Latitude:  d.Location.Lat(),
+				Longitude: d.Location.Lng(),
+			},
+			DistanceKm: d.DistanceKm,
+		})
+	}
+
+	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
+
+	return resp, nil
+}
+
+func (r *shopRepository) GetShopSchedule(ctx context.Context, req *entity.GetShopScheduleRequest) (*entity.ShopScheduleResponse, error) {
+	type dao struct {
+		Timezone        string     `db:"timezone"`
+		IsOpen          bool       `db:"is_open"`
+		VacationStartAt *time.Time `db:"vacation_start_at"`
+		VacationEndAt   *time.Time `db:"vacation_end_at"`
+		VacationMessage *string    `db:"vacation_message"`
+	}
+
+	var (
+		resp = new(entity.ShopScheduleResponse)
+		data = new(dao)
+	)
+	resp.OpeningHours = make([]entity.OpeningHour, 0, 7)
+
+	query := `
+		SELECT
+			timezone,
+			shop_is_open(id) AS is_open,
+			vacation_start_at,
+			vacation_end_at,
+			vacation_message
+		FROM shops
+		WHERE id = ? AND deleted_at IS NULL
+	`
+
+	err := r.db.QueryRowxContext(ctx, r.db.Rebind(query), req.Id).StructScan(data)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("repository::GetShopSchedule - Shop not found")
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Toko tidak ditemukan"))
+		}
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::GetShopSchedule - Failed to get shop")
+		return nil, err
+	}
+
+	query = `
+		SELECT
+			weekday,
+			TO_CHAR(open_time, 'HH24:MI') AS open_time,
+			TO_CHAR(close_time, 'HH24:MI') AS close_time
+		FROM shop_opening_hours
+		WHERE shop_id = ?
+		ORDER BY weekday ASC
+	`
+
+	err = r.db.SelectContext(ctx, &resp.OpeningHours, r.db.Rebind(query), req.Id)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::GetShopSchedule - Failed to get opening hours")
+		return nil, err
+	}
+
+	resp.Timezone = data.Timezone
+	resp.IsOpen = data.IsOpen
+	if data.VacationStartAt != nil {
+		resp.Vacation = &entity.ShopVacation{
+			StartAt: *data.VacationStartAt,
+			EndAt:   data.VacationEndAt,
+			Message: data.VacationMessage,
+		}
+	}
+
+	return resp, nil
+}
+
+func (r *shopRepository) UpdateShopSchedule(ctx context.Context, req *entity.UpdateShopScheduleRequest) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::UpdateShopSchedule - Failed to begin transaction")
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				log.Ctx(ctx).Error().Err(errRollback).Any("payload", req).Msg("repository::UpdateShopSchedule - Failed to rollback transaction")
+			}
+		}
+	}()
+
+	query := `
+		UPDATE shops
+		SET timezone = ?, updated_at = NOW()
+		WHERE id = ? AND user_id = ? AND deleted_at IS NULL
+		RETURNING id
+	`
+
+	var id string
+	err = tx.QueryRowxContext(ctx, tx.Rebind(query), req.Timezone, req.Id, req.UserId).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("repository::UpdateShopSchedule - Shop not found")
+			err = errmsg.NewCustomErrors(404, errmsg.WithMessage("Toko tidak ditemukan"))
+			return err
+		}
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::UpdateShopSchedule - Failed to update shop")
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(`DELETE FROM shop_opening_hours WHERE shop_id = ?`), req.Id)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::UpdateShopSchedule - Failed to clear opening hours")
+		return err
+	}
+
+	if len(req.OpeningHours) > 0 {
+		rows := make([]map[string]any, 0, len(req.OpeningHours))
+		for _, h := range req.OpeningHours {
+			rows = append(rows, map[string]any{
+				"shop_id":    req.Id,
+				"weekday":    h.Weekday,
+				"open_time":  h.OpenTime,
+				"close_time": h.CloseTime,
+			})
+		}
+
+		_, err = tx.NamedExecContext(ctx, `
+			INSERT INTO shop_opening_hours (shop_id, weekday, open_time, close_time)
+			VALUES (:shop_id, :weekday, :open_time, :close_time)
+		`, rows)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::UpdateShopSchedule - Failed to insert opening hours")
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::UpdateShopSchedule - Failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
+
+func (r *shopRepository) SetShopVacation(ctx context.Context, req *entity.SetShopVacationRequest) error {
+	query := `
+		UPDATE shops
+		SET
+			vacation_start_at = ?,
+			vacation_end_at = ?,
+			vacation_message = ?,
+			updated_at = NOW()
+		WHERE id = ? AND user_id = ? AND deleted_at IS NULL
+	`
+
+	res, err := r.db.ExecContext(ctx, r.db.Rebind(query),
+		req.StartAt,
+		req.EndAt,
+		req.Message,
+		req.Id,
+		req.UserId)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::SetShopVacation - Failed to set vacation")
+		return err
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		log.Ctx(ctx).Warn().Any("payload", req).Msg("repository::SetShopVacation - Shop not found")
+		return errmsg.NewCustomErrors(404, errmsg.WithMessage("Toko tidak ditemukan"))
+	}
+
+	return nil
+}
+
+func (r *shopRepository) EndShopVacation(ctx context.Context, req *entity.EndShopVacationRequest) error {
+	query := `
+		UPDATE shops
+		SET
+			vacation_start_at = NULL,
+			vacation_end_at = NULL,
+			vacation_message = NULL,
+			updated_at = NOW()
+		WHERE id = ? AND user_id = ? AND deleted_at IS NULL
+	`
+
+	res, err := r.db.ExecContext(ctx, r.db.Rebind(query), req.Id, req.UserId)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::EndShopVacation - Failed to end vacation")
+		return err
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		log.Ctx(ctx).Warn().Any("payload", req).Msg("repository::EndShopVacation - Shop not found")
+		return errmsg.NewCustomErrors(404, errmsg.WithMessage("Toko tidak ditemukan"))
+	}
+
+	return nil
+}
+
+func (r *shopRepository) IsShopOpen(ctx context.Context, shopId string) (bool, error) {
+	var isOpen sql.NullBool
+
+	err := r.db.QueryRowxContext(ctx, r.db.Rebind(`SELECT shop_is_open(?)`), shopId).Scan(&isOpen)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("shop_id", shopId).Msg("repository::IsShopOpen - Failed to check shop status")
+		return false, err
+	}
+
+	// shop_is_open returns NULL for an unknown shop
+	if !isOpen.Valid {
+		log.Ctx(ctx).Warn().Str("shop_id", shopId).Msg("repository::IsShopOpen - Shop not found")
+		return false, errmsg.NewCustomErrors(404, errmsg.WithMessage("Toko tidak ditemukan"))
+	}
+
+	return isOpen.Bool, nil
+}
+
+func (r *shopRepository) CreateShopVerification(ctx context.Context, req *entity.CreateShopVerificationRequest) (*entity.CreateShopVerificationResponse, error) {
+	var (
+		resp     = new(entity.CreateShopVerificationResponse)
+		verified bool
+	)
+
+	query := `
+		SELECT verified_at IS NOT NULL
+		FROM shops
+		WHERE id = ? AND user_id = ? AND deleted_at IS NULL
+	`
+
+	err := r.db.QueryRowxContext(ctx, r.db.Rebind(query), req.ShopId, req.UserId).Scan(&verified)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("repository::CreateShopVerification - Shop not found")
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Toko tidak ditemukan"))
+		}
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::CreateShopVerification - Failed to get shop")
+		return nil, err
+	}
+
+	if verified {
+		return nil, errmsg.NewCustomErrors(409, errmsg.WithMessage("Toko sudah terverifikasi"))
+	}
+
+	query = `
+		INSERT INTO shop_verifications (shop_id, user_id, ktp_file, npwp_file)
+		VALUES (?, ?, ?, ?)
+		RETURNING id, status
+	`
+
+	err = r.db.QueryRowxContext(ctx, r.db.Rebind(query),
+		req.ShopId,
+		req.UserId,
+		req.KtpFile,
+		req.NpwpFile).Scan(&resp.Id, &resp.Status)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
+			log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("repository::CreateShopVerification - Pending request exists")
+			return nil, errmsg.NewCustomErrors(409, errmsg.WithMessage("Pengajuan verifikasi toko masih diproses"))
+		}
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::CreateShopVerification - Failed to create verification")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *shopRepository) GetShopVerification(ctx context.Context, req *entity.GetShopVerificationRequest) (*entity.ShopVerification, error) {
+	var resp = new(entity.ShopVerification)
+
+	query := `
+		SELECT
+			v.id,
+			v.shop_id,
+			s.name AS shop_name,
+			v.status,
+			v.ktp_file,
+			v.npwp_file,
+			v.reject_reason,
+			v.reviewed_at,
+			v.created_at
+		FROM shop_verifications v
+		JOIN shops s ON s.id = v.shop_id
+		WHERE
+			v.shop_id = ?
+			AND s.user_id = ?
+			AND s.deleted_at IS NULL
+		ORDER BY v.created_at DESC
+		LIMIT 1
+	`
+
+	err := r.db.QueryRowxContext(ctx, r.db.Rebind(query), req.ShopId, req.UserId).StructScan(resp)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("repository::GetShopVerification - Verification not found")
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Pengajuan verifikasi tidak ditemukan"))
+		}
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::GetShopVerification - Failed to get verification")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *shopRepository) GetShopVerifications(ctx context.Context, req *entity.ShopVerificationsRequest) (*entity.ShopVerificationsResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.ShopVerification
+	}
+
+	var (
+		resp = new(entity.ShopVerificationsResponse)
+		data = make([]dao, 0, req.Paginate)
+		args = make([]any, 0, 3)
+	)
+	resp.Items = make([]entity.ShopVerification, 0, req.Paginate)
+
+	query := `
+		SELECT
+			COUNT(v.id) OVER() as total_data,
+			v.id,
+			v.shop_id,
+			s.name AS shop_name,
+			v.status,
+			v.ktp_file,
+			v.npwp_file,
+			v.reject_reason,
+			v.reviewed_at,
+			v.created_at
+		FROM shop_verifications v
+		JOIN shops s ON s.id = v.shop_id
+		WHERE s.deleted_at IS NULL
+	`
+
+	if req.Status != "" {
+		query += " AND v.status = ?"
+		args = append(args, req.Status)
+	}
+
+	query += " ORDER BY v.created_at ASC LIMIT ? OFFSET ?"
+	args = append(args, req.Paginate, req.Paginate*(req.Page-1))
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query), args...)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::GetShopVerifications - Failed to get verifications")
+		return nil, err
+	}
+
+	if len(data) > 0 {
+		resp.Meta.TotalData = data[0].TotalData
+	}
+
+	for _, d := range data {
+		resp.Items = append(resp.Items, d.ShopVerification)
+	}
+
+	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
+
+	return resp, nil
+}
+
+func (r *shopRepository) ReviewShopVerification(ctx context.Context, req *entity.ReviewShopVerificationRequest) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::ReviewShopVerification - Failed to begin transaction")
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				log.Ctx(ctx).Error().Err(errRollback).Any("payload", req).Msg("repository::ReviewShopVerification - Failed to rollback transaction")
+			}
+		}
+	}()
+
+	var (
+		current entity.VerificationStatus
+		shopId  string
+	)
+
+	query := `
+		SELECT status, shop_id
+		FROM shop_verifications
+		WHERE id = ?
+		FOR UPDATE
+	`
+
+	err = tx.QueryRowxContext(ctx, tx.Rebind(query), req.Id).Scan(&current, &shopId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("repository::ReviewShopVerification - Verification not found")
+			err = errmsg.NewCustomErrors(404, errmsg.WithMessage("Pengajuan verifikasi tidak ditemukan"))
+			return err
+		}
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::ReviewShopVerification - Failed to get verification")
+		return err
+	}
+
+	if !current.CanTransitionTo(req.Status) {
+		log.Ctx(ctx).Warn().Any("payload", req).Str("current", string(current)).Msg("repository::ReviewShopVerification - Invalid transition")
+		err = errmsg.NewCustomErrors(409, errmsg.WithMessage("Pengajuan verifikasi sudah ditinjau"))
+		return err
+	}
+
+	query = `
+		UPDATE shop_verifications
+		SET
+			status = ?,
+			reject_reason = ?,
+			reviewed_by = ?,
+			reviewed_at = NOW(),
+			updated_at = NOW()
+		WHERE id = ?
+	`
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(query), req.Status, req.Reason, req.ReviewerId, req.Id)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::ReviewShopVerification - Failed to update verification")
+		return err
+	}
+
+	if req.Status == entity.VerificationApproved {
+		_, err = tx.ExecContext(ctx, tx.Rebind(`UPDATE shops SET verified_at = NOW(), updated_at = NOW() WHERE id = ?`), shopId)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::ReviewShopVerification - Failed to verify shop")
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::ReviewShopVerification - Failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
+
+func (r *shopRepository) GetTrash(ctx context.Context, req *entity.TrashRequest) (*entity.TrashResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.TrashItem
+	}
+
+	var (
+		resp = new(entity.TrashResponse)
+		data = make([]dao, 0, req.Paginate)
+	)
+	resp.Items = make([]entity.TrashItem, 0, req.Paginate)
+
+	query := `
+		SELECT
+			COUNT(id) OVER() as total_data,
+			id,
+			name,
+			slug,
+			deleted_at
+		FROM shops
+		WHERE
+			deleted_at IS NOT NULL
+			AND user_id = ?
+		ORDER BY deleted_at DESC
+		LIMIT ? OFFSET ?
+	`
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query),
+		req.UserId,
+		req.Paginate,
+		req.Paginate*(req.Page-1),
+	)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::GetTrash - Failed to get deleted shops")
+		return nil, err
+	}
+
+	if len(data) > 0 {
+		resp.Meta.TotalData = data[0].TotalData
+	}
+
+	for _, d := range data {
+		resp.Items = append(resp.Items, d.TrashItem)
+	}
+
+	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
+
+	return resp, nil
+}
+
+// RestoreShop brings the shop back together with the products that were
+// deleted along with it; products deleted on their own stay in the trash.
+func (r *shopRepository) RestoreShop(ctx context.Context, req *entity.RestoreShopRequest) (err error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::RestoreShop - Failed to begin transaction")
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				log.Ctx(ctx).Error().Err(errRollback).Any("payload", req).Msg("repository::RestoreShop - Failed to rollback transaction")
+			}
+		}
+	}()
+
+	query := `
+		WITH old AS (
+			SELECT id, deleted_at
+			FROM shops
+			WHERE id = ? AND user_id = ? AND deleted_at IS NOT NULL
+			FOR UPDATE
+		)
+		UPDATE shops
+		SET deleted_at = NULL, updated_at = NOW()
+		FROM old
+		WHERE shops.id = old.id
+		RETURNING old.deleted_at
+	`
+
+	var deletedAt time.Time
+	err = tx.QueryRowxContext(ctx, tx.Rebind(query), req.Id, req.UserId).Scan(&deletedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Ctx(ctx).Warn().Any("payload", req).Msg("repository::RestoreShop - Deleted shop not found")
+			err = errmsg.NewCustomErrors(404, errmsg.WithMessage("Toko tidak ditemukan di tempat sampah"))
+			return err
+		}
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::RestoreShop - Failed to restore shop")
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(`
+		UPDATE products
+		SET deleted_at = NULL, updated_at = NOW()
+		WHERE shop_id = ? AND deleted_at = ?
+	`), req.Id, deletedAt)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::RestoreShop - Failed to restore shop products")
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::RestoreShop - Failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
+
+func (r *shopRepository) PurgeShops(ctx context.Context, req *entity.PurgeRequest) (resp *entity.PurgeResponse, err error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::PurgeShops - Failed to begin transaction")
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				log.Ctx(ctx).Error().Err(errRollback).Any("payload", req).Msg("repository::PurgeShops - Failed to rollback transaction")
+			}
+		}
+	}()
+
+	resp = &entity.PurgeResponse{
+		Ids:   make([]string, 0),
+		Files: make([]string, 0),
+	}
+
+	// shops that still own products (even soft-deleted ones) wait until
+	// those products are purged
+	err = tx.SelectContext(ctx, &resp.Ids, tx.Rebind(`
+		SELECT s.id
+		FROM shops s
+		WHERE
+			s.deleted_at IS NOT NULL
+			AND s.deleted_at < ?
+			AND NOT EXISTS (SELECT 1 FROM products p WHERE p.shop_id = s.id)
+		FOR UPDATE
+	`), req.Before)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::PurgeShops - Failed to get shops to purge")
+		return nil, err
+	}
+
+	if len(resp.Ids) == 0 {
+		err = tx.Commit()
+		return resp, err
+	}
+
+	var documents []struct {
+		KtpFile  string `db:"ktp_file"`
+		NpwpFile string `db:"npwp_file"`
+	}
+
+	err = tx.SelectContext(ctx, &documents, tx.Rebind(`
+		SELECT ktp_file, npwp_file
+		FROM shop_verifications
+		WHERE shop_id = ANY(?)
+	`), pq.Array(resp.Ids))
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::PurgeShops - Failed to get verification documents")
+		return nil, err
+	}
+
+	for _, d := range documents {
+		resp.Files = append(resp.Files, d.KtpFile, d.NpwpFile)
+	}
+
+	// opening hours cascade
+	_, err = tx.ExecContext(ctx, tx.Rebind(`DELETE FROM shop_verifications WHERE shop_id = ANY(?)`), pq.Array(resp.Ids))
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::PurgeShops - Failed to delete verifications")
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(`DELETE FROM shops WHERE id = ANY(?)`), pq.Array(resp.Ids))
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::PurgeShops - Failed to delete shops")
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repository::PurgeShops - Failed to commit transaction")
+		return nil, err
+	}
+
+	return resp, nil
+}
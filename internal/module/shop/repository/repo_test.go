@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNearbyShopsQueryCompiles compiles the named query like
+// SelectNamedContext does, a "::" cast would come out as a single ":".
+func TestNearbyShopsQueryCompiles(t *testing.T) {
+	compiled, names, err := sqlx.Named(nearbyShopsQuery, map[string]any{
+		"lat": -6.2, "lng": 106.8, "radius_m": 10000, "paginate": 10, "offset": 0,
+	})
+	require.NoError(t, err)
+	compiled = sqlx.Rebind(sqlx.DOLLAR, compiled)
+
+	assert.Len(t, names, 7)
+	assert.NotContains(t, compiled, ":")
+	assert.Contains(t, compiled, "ST_Distance(location, CAST(ST_MakePoint($1, $2) AS geography))")
+	assert.Contains(t, compiled, "ST_DWithin(location, CAST(ST_MakePoint($3, $4) AS geography), $5)")
+}
@@ -1,31 +1,67 @@
 package service
 
 import (
+	"codebase-app/internal/infrastructure/config"
+	"codebase-app/internal/infrastructure/metrics"
+	integration "codebase-app/internal/integration/localstorage"
 	"codebase-app/internal/module/shop/entity"
 	"codebase-app/internal/module/shop/ports"
+	"codebase-app/pkg"
+	"codebase-app/pkg/database"
+	"codebase-app/pkg/errmsg"
+	storage "codebase-app/pkg/storage-manager"
 	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog/log"
 )
 
 var _ ports.ShopService = &shopService{}
 
+// verificationUrlExpiration is how long a signed KTP/NPWP document url stays valid.
+const verificationUrlExpiration = 15 * time.Minute
+
 type shopService struct {
-	repo ports.ShopRepository
+	repo    ports.ShopRepository
+	storage integration.LocalStorageContract
 }
 
-func NewShopService(repo ports.ShopRepository) *shopService {
+func NewShopService(repo ports.ShopRepository, storage integration.LocalStorageContract) *shopService {
 	return &shopService{
-		repo: repo,
+		repo:    repo,
+		storage: storage,
 	}
 }
 
 func (s *shopService) CreateShop(ctx context.Context, req *entity.CreateShopRequest) (*entity.CreateShopResponse, error) {
-	return s.repo.CreateShop(ctx, req)
+	if req.Slug == "" {
+		req.Slug = pkg.Slugify(req.Name)
+	}
+
+	if req.Slug == "" {
+		return nil, errmsg.NewCustomErrors(400, errmsg.WithErrors("slug", "slug tidak dapat dibuat dari nama toko, silakan isi slug."))
+	}
+
+	resp, err := s.repo.CreateShop(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics.ShopsCreated.Inc()
+	return resp, nil
 }
 
 func (s *shopService) GetShop(ctx context.Context, req *entity.GetShopRequest) (*entity.GetShopResponse, error) {
 	return s.repo.GetShop(ctx, req)
 }
 
+func (s *shopService) GetShopBySlug(ctx context.Context, req *entity.GetShopBySlugRequest) (*entity.GetShopResponse, error) {
+	return s.repo.GetShopBySlug(ctx, req)
+}
+
 func (s *shopService) DeleteShop(ctx context.Context, req *entity.DeleteShopRequest) error {
 	return s.repo.DeleteShop(ctx, req)
 }
@@ -37,3 +73,156 @@ func (s *shopService) UpdateShop(ctx context.Context, req *entity.UpdateShopRequ
 func (s *shopService) GetShops(ctx context.Context, req *entity.ShopsRequest) (*entity.ShopsResponse, error) {
 	return s.repo.GetShops(ctx, req)
 }
+
+func (s *shopService) GetNearbyShops(ctx context.Context, req *entity.NearbyShopsRequest) (*entity.NearbyShopsResponse, error) {
+	return s.repo.GetNearbyShops(ctx, req)
+}
+
+func (s *shopService) GetShopSchedule(ctx context.Context, req *entity.GetShopScheduleRequest) (*entity.ShopScheduleResponse, error) {
+	return s.repo.GetShopSchedule(ctx, req)
+}
+
+func (s *shopService) UpdateShopSchedule(ctx context.Context, req *entity.UpdateShopScheduleRequest) error {
+	return s.repo.UpdateShopSchedule(ctx, req)
+}
+
+func (s *shopService) SetShopVacation(ctx context.Context, req *entity.SetShopVacationRequest) error {
+	return s.repo.SetShopVacation(ctx, req)
+}
+
+func (s *shopService) EndShopVacation(ctx context.Context, req *entity.EndShopVacationRequest) error {
+	return s.repo.EndShopVacation(ctx, req)
+}
+
+func (s *shopService) IsShopOpen(ctx context.Context, shopId string) (bool, error) {
+	return s.repo.IsShopOpen(ctx, shopId)
+}
+
+func (s *shopService) CreateShopVerification(ctx context.Context, req *entity.CreateShopVerificationRequest) (*entity.CreateShopVerificationResponse, error) {
+	var (
+		privatePath = config.Envs.App.LocalStoragePrivatePath
+		dir         = filepath.Join(privatePath, "shop-verifications", req.ShopId)
+		saved       = make([]string, 0, 2)
+	)
+
+	// documents only live in the private storage, they are served through signed urls
+	for _, doc := range []struct {
+		content string
+		field   string
+		dest    *string
+	}{
+		{req.KtpDocument, "ktp_document", &req.KtpFile},
+		{req.NpwpDocument, "npwp_document", &req.NpwpFile},
+	} {
+		fullpath, errSave := s.storage.Save(doc.content, dir)
+		if errSave != nil {
+			s.removeFiles(ctx, saved)
+			if errors.Is(errSave, integration.ErrFileTypeNotSupported) {
+				return nil, errmsg.NewCustomErrors(400, errmsg.WithErrors(doc.field, "file harus berupa jpg, png, atau pdf."))
+			}
+			return nil, errSave
+		}
+
+		saved = append(saved, fullpath)
+		*doc.dest = storageKey(privatePath, fullpath)
+	}
+
+	resp, err := s.repo.CreateShopVerification(ctx, req)
+	if err != nil {
+		s.removeFiles(ctx, saved)
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (s *shopService) GetShopVerification(ctx context.Context, req *entity.GetShopVerificationRequest) (*entity.ShopVerification, error) {
+	resp, err := s.repo.GetShopVerification(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.signVerification(resp)
+
+	return resp, nil
+}
+
+func (s *shopService) GetShopVerifications(ctx context.Context, req *entity.ShopVerificationsRequest) (*entity.ShopVerificationsResponse, error) {
+	resp, err := s.repo.GetShopVerifications(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range resp.Items {
+		s.signVerification(&resp.Items[i])
+	}
+
+	return resp, nil
+}
+
+func (s *shopService) ReviewShopVerification(ctx context.Context, req *entity.ReviewShopVerificationRequest) error {
+	return s.repo.ReviewShopVerification(ctx, req)
+}
+
+func (s *shopService) GetTrash(ctx context.Context, req *entity.TrashRequest) (*entity.TrashResponse, error) {
+	return s.repo.GetTrash(ctx, req)
+}
+
+func (s *shopService) RestoreShop(ctx context.Context, req *entity.RestoreShopRequest) error {
+	return s.repo.RestoreShop(ctx, req)
+}
+
+// PurgeShops hard-deletes old soft-deleted shops, then removes their
+// verification documents from the private storage once the deletion is
+// committed.
+func (s *shopService) PurgeShops(ctx context.Context, req *entity.PurgeRequest) (int64, error) {
+	resp, err := s.repo.PurgeShops(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		privatePath = config.Envs.App.LocalStoragePrivatePath
+		files       = make([]string, 0, len(resp.Files))
+	)
+
+	for _, f := range resp.Files {
+		files = append(files, filepath.Join(privatePath, filepath.Clean("/"+f)))
+	}
+
+	database.AfterCommit(ctx, func() {
+		s.removeFiles(ctx, files)
+
+		for _, id := range resp.Ids {
+			// only succeeds once the directory is empty
+			_ = os.Remove(filepath.Join(privatePath, "shop-verifications", id))
+		}
+	})
+
+	return int64(len(resp.Ids)), nil
+}
+
+func (s *shopService) signVerification(v *entity.ShopVerification) {
+	v.KtpUrl = storage.GenerateSignedURL(v.KtpFile, verificationUrlExpiration)
+	v.NpwpUrl = storage.GenerateSignedURL(v.NpwpFile, verificationUrlExpiration)
+}
+
+// storageKey is fullpath relative to the storage root, the form signed urls
+// and PurgeShops expect. Both are cleaned first, filepath.Join turns a
+// "./storage/private" root into "storage/private".
+func storageKey(root, fullpath string) string {
+	rel, err := filepath.Rel(filepath.Clean(root), filepath.Clean(fullpath))
+	if err != nil {
+		return filepath.ToSlash(fullpath)
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+func (s *shopService) removeFiles(ctx context.Context, paths []string) {
+	for _, p := range paths {
+		if err := os.Remove(p); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Str("path", p).Msg("service::removeFiles - Failed to remove file")
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"codebase-app/internal/infrastructure/config"
+	integration "codebase-app/internal/integration/localstorage"
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/internal/module/shop/ports"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// a 1x1 png
+const pngDocument = "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAQAAAC1HAwCAAAAC0lEQVR42mNkYAAAAAYAAjCB0C8AAAAASUVORK5CYII="
+
+type fakeRepo struct {
+	ports.ShopRepository
+
+	verification *entity.CreateShopVerificationRequest
+	purged       *entity.PurgeResponse
+}
+
+func (r *fakeRepo) CreateShopVerification(_ context.Context, req *entity.CreateShopVerificationRequest) (*entity.CreateShopVerificationResponse, error) {
+	r.verification = req
+	return &entity.CreateShopVerificationResponse{}, nil
+}
+
+func (r *fakeRepo) PurgeShops(context.Context, *entity.PurgeRequest) (*entity.PurgeResponse, error) {
+	return r.purged, nil
+}
+
+func TestStorageKey(t *testing.T) {
+	tests := []struct {
+		root, fullpath, want string
+	}{
+		{"./storage/private", "storage/private/shop-verifications/1/a.png", "shop-verifications/1/a.png"},
+		{"storage/private/", "storage/private/shop-verifications/1/a.png", "shop-verifications/1/a.png"},
+		{"/srv/private", "/srv/private/shop-verifications/1/a.png", "shop-verifications/1/a.png"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, storageKey(tt.root, tt.fullpath), tt.root)
+	}
+}
+
+// TestShopVerificationDocuments saves the documents under a "./" prefixed
+// private path, stores them relative to it and purges them from there.
+func TestShopVerificationDocuments(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	config.Envs = &config.Config{}
+	config.Envs.App.LocalStoragePrivatePath = "./storage/private"
+
+	var (
+		ctx  = context.Background()
+		repo = &fakeRepo{}
+		svc  = NewShopService(repo, integration.NewLocalStorageIntegration())
+	)
+
+	_, err = svc.CreateShopVerification(ctx, &entity.CreateShopVerificationRequest{
+		ShopId:       "9aa56858-974a-4c5a-9aa8-0fcce63430f7",
+		KtpDocument:  pngDocument,
+		NpwpDocument: pngDocument,
+	})
+	require.NoError(t, err)
+
+	files := []string{repo.verification.KtpFile, repo.verification.NpwpFile}
+	for _, f := range files {
+		assert.True(t, strings.HasPrefix(f, "shop-verifications/9aa56858-974a-4c5a-9aa8-0fcce63430f7/"), f)
+		assert.FileExists(t, filepath.Join("storage/private", f))
+	}
+
+	repo.purged = &entity.PurgeResponse{Ids: []string{"9aa56858-974a-4c5a-9aa8-0fcce63430f7"}, Files: files}
+	_, err = svc.PurgeShops(ctx, &entity.PurgeRequest{})
+	require.NoError(t, err)
+
+	for _, f := range files {
+		assert.NoFileExists(t, filepath.Join("storage/private", f))
+	}
+	_, err = os.Stat("storage/private/shop-verifications/9aa56858-974a-4c5a-9aa8-0fcce63430f7")
+	assert.True(t, os.IsNotExist(err))
+}
@@ -54,17 +54,17 @@ func (h *userHandler) Register(router fiber.Router) {
 func (h *userHandler) register(c *fiber.Ctx) error {
 	var (
 		req = new(entity.RegisterRequest)
-		ctx = c.Context()
+		ctx = c.UserContext()
 		v   = adapter.Adapters.Validator
 	)
 
 	if err := c.BodyParser(req); err != nil {
-		log.Warn().Err(err).Msg("handler::register - Failed to parse request body")
+		log.Ctx(ctx).Warn().Err(err).Msg("handler::register - Failed to parse request body")
 		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
 	}
 
 	if err := v.Validate(req); err != nil {
-		log.Warn().Err(err).Msg("handler::register - Invalid request body")
+		log.Ctx(ctx).Warn().Err(err).Msg("handler::register - Invalid request body")
 		code, errs := errmsg.Errors(err, req)
 		return c.Status(code).JSON(response.Error(errs))
 	}
@@ -81,17 +81,17 @@ func (h *userHandler) register(c *fiber.Ctx) error {
 func (h *userHandler) login(c *fiber.Ctx) error {
 	var (
 		req = new(entity.LoginRequest)
-		ctx = c.Context()
+		ctx = c.UserContext()
 		v   = adapter.Adapters.Validator
 	)
 
 	if err := c.BodyParser(req); err != nil {
-		log.Warn().Err(err).Msg("handler::login - Failed to parse request body")
+		log.Ctx(ctx).Warn().Err(err).Msg("handler::login - Failed to parse request body")
 		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
 	}
 
 	if err := v.Validate(req); err != nil {
-		log.Warn().Err(err).Msg("handler::login - Invalid request body")
+		log.Ctx(ctx).Warn().Err(err).Msg("handler::login - Invalid request body")
 		code, errs := errmsg.Errors(err, req)
 		return c.Status(code).JSON(response.Error(errs))
 	}
@@ -108,14 +108,14 @@ func (h *userHandler) login(c *fiber.Ctx) error {
 func (h *userHandler) profileByUserId(c *fiber.Ctx) error {
 	var (
 		req = new(entity.ProfileRequest)
-		ctx = c.Context()
+		ctx = c.UserContext()
 		v   = adapter.Adapters.Validator
 	)
 
 	req.UserId = c.Params("user_id")
 
 	if err := v.Validate(req); err != nil {
-		log.Warn().Err(err).Msg("handler::profileByUserId - Invalid Request")
+		log.Ctx(ctx).Warn().Err(err).Msg("handler::profileByUserId - Invalid Request")
 		code, errs := errmsg.Errors(err, req)
 		return c.Status(code).JSON(response.Error(errs))
 	}
@@ -132,7 +132,7 @@ func (h *userHandler) profileByUserId(c *fiber.Ctx) error {
 func (h *userHandler) profile(c *fiber.Ctx) error {
 	var (
 		req = new(entity.ProfileRequest)
-		ctx = c.Context()
+		ctx = c.UserContext()
 		l   = middleware.GetLocals(c)
 	)
 
@@ -153,7 +153,7 @@ func (h *userHandler) oauthGoogleUrl(c *fiber.Ctx) error {
 
 func (h *userHandler) callbackSigninGoogle(c *fiber.Ctx) error {
 	var (
-		ctx = c.Context()
+		ctx = c.UserContext()
 	)
 
 	state, code := c.FormValue("state"), c.FormValue("code")
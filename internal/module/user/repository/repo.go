@@ -3,6 +3,7 @@ package repository
 import (
 	"codebase-app/internal/module/user/entity"
 	"codebase-app/internal/module/user/ports"
+	"codebase-app/pkg/database"
 	"codebase-app/pkg/errmsg"
 	"context"
 	"database/sql"
@@ -15,12 +16,12 @@ import (
 var _ ports.UserRepository = &userRepository{}
 
 type userRepository struct {
-	db *sqlx.DB
+	db *database.DB
 }
 
 func NewUserRepository(db *sqlx.DB) *userRepository {
 	return &userRepository{
-		db: db,
+		db: database.New(db),
 	}
 }
 
@@ -45,16 +46,16 @@ func (r *userRepository) Register(ctx context.Context, req *entity.RegisterReque
 	if err != nil {
 		pqErr, ok := err.(*pq.Error)
 		if !ok {
-			log.Error().Err(err).Any("payload", req).Msg("repo::Register - Failed to insert user")
+			log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repo::Register - Failed to insert user")
 			return nil, err
 		}
 
 		switch pqErr.Code.Name() {
 		case "unique_violation":
-			log.Warn().Err(err).Any("payload", req).Msg("repo::Register - Email already registered")
+			log.Ctx(ctx).Warn().Err(err).Any("payload", req).Msg("repo::Register - Email already registered")
 			return nil, errmsg.NewCustomErrors(409, errmsg.WithMessage("Email sudah terdaftar"))
 		default:
-			log.Error().Err(err).Any("payload", req).Msg("repo::Register - Failed to insert user")
+			log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("repo::Register - Failed to insert user")
 			return nil, err
 		}
 	}
@@ -83,10 +84,10 @@ func (r *userRepository) FindByEmail(ctx context.Context, email string) (*entity
 	err := r.db.GetContext(ctx, res, r.db.Rebind(query), email)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			log.Warn().Err(err).Str("email", email).Msg("repo::FindByEmail - User not found")
+			log.Ctx(ctx).Warn().Err(err).Str("email", email).Msg("repo::FindByEmail - User not found")
 			return nil, errmsg.NewCustomErrors(400, errmsg.WithMessage("Email atau password salah"))
 		}
-		log.Error().Err(err).Str("email", email).Msg("repo::FindByEmail - Failed to get user")
+		log.Ctx(ctx).Error().Err(err).Str("email", email).Msg("repo::FindByEmail - Failed to get user")
 		return nil, err
 	}
 
@@ -113,11 +114,11 @@ func (r *userRepository) FindById(ctx context.Context, id string) (*entity.Profi
 	err := r.db.GetContext(ctx, res, r.db.Rebind(query), id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			log.Warn().Err(err).Str("id", id).Msg("repo::FindById - User not found")
+			log.Ctx(ctx).Warn().Err(err).Str("id", id).Msg("repo::FindById - User not found")
 			return nil, errmsg.NewCustomErrors(400, errmsg.WithMessage("User tidak ditemukan"))
 		}
 
-		log.Error().Err(err).Str("id", id).Msg("repo::FindById - Failed to get user")
+		log.Ctx(ctx).Error().Err(err).Str("id", id).Msg("repo::FindById - Failed to get user")
 		return nil, err
 	}
 
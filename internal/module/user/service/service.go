@@ -32,7 +32,7 @@ func (s *userService) Register(ctx context.Context, req *entity.RegisterRequest)
 
 	hashed, err := pkg.HashPassword(req.Password)
 	if err != nil {
-		log.Error().Err(err).Any("payload", req).Msg("service::Register - Failed to hash password")
+		log.Ctx(ctx).Error().Err(err).Any("payload", req).Msg("service::Register - Failed to hash password")
 		return nil, errmsg.NewCustomErrors(500, errmsg.WithMessage("Gagal menghash password"))
 	}
 
@@ -55,7 +55,7 @@ func (s *userService) Login(ctx context.Context, req *entity.LoginRequest) (*ent
 	}
 
 	if !pkg.ComparePassword(user.Pass, req.Password) {
-		log.Warn().Any("payload", req).Msg("service::Login - Password not match")
+		log.Ctx(ctx).Warn().Any("payload", req).Msg("service::Login - Password not match")
 		return nil, errmsg.NewCustomErrors(401, errmsg.WithMessage("Email atau password salah"))
 	}
 
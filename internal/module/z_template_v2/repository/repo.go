@@ -2,6 +2,7 @@ package repository
 
 import (
 	"codebase-app/internal/module/z_template_v2/ports"
+	"codebase-app/pkg/database"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -9,11 +10,11 @@ import (
 var _ ports.XxxRepository = &xxxRepository{}
 
 type xxxRepository struct {
-	db *sqlx.DB
+	db *database.DB
 }
 
 func NewXxxRepository(db *sqlx.DB) *xxxRepository {
 	return &xxxRepository{
-		db: db,
+		db: database.New(db),
 	}
 }
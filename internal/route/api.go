@@ -1,7 +1,11 @@
 package route
 
 import (
+	"codebase-app/internal/middleware"
+	handlerCurrencies "codebase-app/internal/module/currencies/handler/rest"
 	handlerProductCategories "codebase-app/internal/module/product-categories/handler/rest"
+	handlerProductInquiries "codebase-app/internal/module/product-inquiries/handler/rest"
+	handlerProductReviews "codebase-app/internal/module/product-reviews/handler/rest"
 	handlerProducts "codebase-app/internal/module/products/handler/rest"
 	handlerShop "codebase-app/internal/module/shop/handler/rest"
 	"codebase-app/pkg/response"
@@ -11,32 +15,31 @@ import (
 )
 
 func SetupRoutes(app *fiber.App) {
+	describe()
+
 	var (
-		api = app.Group("/products")
+		api = app.Group("/products", middleware.RateLimit(middleware.RateLimitByMethod))
 	)
 
 	handlerShop.NewShopHandler().Register(api)
 	handlerProductCategories.NewProductCategoriesHandler().Register(api)
+	handlerProductReviews.NewReviewHandler().Register(api)
+	handlerProductInquiries.NewInquiryHandler().Register(api)
+	handlerCurrencies.NewCurrencyHandler().Register(api)
 	handlerProducts.NewProductsHandler().Register(api)
 
+	app.Get("/api/storage/private/*", middleware.RateLimit(middleware.RateLimitRead), middleware.ValidateSignedURL, privateStorage)
+
+	app.Get("/healthz", healthz)
+	app.Get("/readyz", readyz)
+
+	app.Get("/openapi.json", openapiSpec)
+	app.Get("/docs/*", docsUI)
+
 	// fallback route
 	app.Use(func(c *fiber.Ctx) error {
-		var (
-			method = c.Method()                       // get the request method
-			path   = c.Path()                         // get the request path
-			query  = c.Context().QueryArgs().String() // get all query params
-			ua     = c.Get("User-Agent")              // get the request user agent
-			ip     = c.IP()                           // get the request IP
-		)
-
-		log.Info().
-			Str("url", c.OriginalURL()).
-			Str("method", method).
-			Str("path", path).
-			Str("query", query).
-			Str("ua", ua).
-			Str("ip", ip).
-			Msg("Route not found.")
+		// method, path, ip and user agent are in the access log line
+		log.Ctx(c.UserContext()).Info().Str("url", c.OriginalURL()).Msg("Route not found.")
 		return c.Status(fiber.StatusNotFound).JSON(response.Error("Route not found"))
 	})
 }
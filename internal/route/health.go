@@ -0,0 +1,55 @@
+package route
+
+import (
+	"codebase-app/internal/adapter"
+	"codebase-app/internal/infrastructure/config"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+type liveness struct {
+	Status string `json:"status"`
+}
+
+type readiness struct {
+	Status     string                             `json:"status"` // "ready" or "not_ready"
+	Draining   bool                               `json:"draining"`
+	Components map[string]adapter.ComponentStatus `json:"components"`
+}
+
+// healthz is the liveness probe: the process is up and serving requests.
+// It checks no dependency, a database outage must not get the pod restarted.
+func healthz(c *fiber.Ctx) error {
+	return c.JSON(liveness{Status: "ok"})
+}
+
+// readyz is the readiness probe: every synced adapter answers and the server
+// is not draining. It returns 503 otherwise, with the status per component.
+func readyz(c *fiber.Ctx) error {
+	var (
+		timeout    = time.Duration(config.Envs.Health.CheckTimeout)
+		components = adapter.Adapters.Check(c.UserContext(), timeout)
+		draining   = adapter.Adapters.Draining()
+		ready      = !draining
+	)
+
+	for name, component := range components {
+		if component.Status != "up" {
+			ready = false
+			log.Ctx(c.UserContext()).Warn().Str("component", name).Str("error", component.Error).Msg("route::readyz - Component is down")
+		}
+	}
+
+	status, code := "ready", fiber.StatusOK
+	if !ready {
+		status, code = "not_ready", fiber.StatusServiceUnavailable
+	}
+
+	return c.Status(code).JSON(readiness{
+		Status:     status,
+		Draining:   draining,
+		Components: components,
+	})
+}
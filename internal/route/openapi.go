@@ -0,0 +1,98 @@
+package route
+
+import (
+	"codebase-app/internal/middleware"
+	handlerCurrencies "codebase-app/internal/module/currencies/handler/rest"
+	handlerProductCategories "codebase-app/internal/module/product-categories/handler/rest"
+	handlerProductInquiries "codebase-app/internal/module/product-inquiries/handler/rest"
+	handlerProductReviews "codebase-app/internal/module/product-reviews/handler/rest"
+	handlerProducts "codebase-app/internal/module/products/handler/rest"
+	handlerShop "codebase-app/internal/module/shop/handler/rest"
+	"codebase-app/pkg/openapi"
+	"encoding/json"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/swaggest/swgui/v5emb"
+)
+
+const docsTitle = "Shopeefun Products API"
+
+// Spec documents the routes of SetupRoutes, see docs.go in every handler
+// package. TestSpecMatchesRoutes fails when they drift apart.
+var Spec = openapi.New(openapi.Info{
+	Title:   docsTitle,
+	Version: "1.0.0",
+})
+
+var (
+	specOnce sync.Once
+	specJSON []byte
+)
+
+func describe() {
+	Spec.Describe(handlerShop.Operations...)
+	Spec.Describe(handlerProductCategories.Operations...)
+	Spec.Describe(handlerProductReviews.Operations...)
+	Spec.Describe(handlerProductInquiries.Operations...)
+	Spec.Describe(handlerCurrencies.Operations...)
+	Spec.Describe(handlerProducts.Operations...)
+
+	Spec.Describe(
+		openapi.Operation{
+			Handler:     privateStorage,
+			Summary:     "Download a private file",
+			Description: "The URL is signed by the storage adapter and expires.",
+			ContentType: fiber.MIMEOctetStream,
+		},
+		openapi.Operation{Handler: healthz, Summary: "Liveness probe", Response: liveness{}, Raw: true},
+		openapi.Operation{Handler: readyz, Summary: "Readiness probe, 503 when not ready", Response: readiness{}, Raw: true},
+		openapi.Operation{Handler: openapiSpec, Summary: "This document", Raw: true},
+		openapi.Operation{Handler: docsUI, Summary: "Swagger UI for this document", ContentType: fiber.MIMETextHTML},
+	)
+
+	userId := openapi.Parameter{Name: "X-USER-ID", In: "header", Schema: &openapi.Schema{Type: "string", Format: "uuid"}}
+	required := userId
+	required.Required = true
+
+	Spec.Use(middleware.UserIdHeader, openapi.Middleware{Parameters: []openapi.Parameter{required}})
+	Spec.Use(middleware.OptionalUserIdHeader, openapi.Middleware{Parameters: []openapi.Parameter{userId}})
+	Spec.Use(middleware.AuthBearer, openapi.Middleware{Security: "bearer"})
+	Spec.Use(middleware.IfMatch, openapi.Middleware{Parameters: []openapi.Parameter{{
+		Name:        "If-Match",
+		In:          "header",
+		Description: "The ETag of the last read, the update fails with 412 when it is stale.",
+		Required:    true,
+		Schema:      &openapi.Schema{Type: "string"},
+	}}})
+	Spec.Use(middleware.Idempotency(), openapi.Middleware{Parameters: []openapi.Parameter{{
+		Name:        "Idempotency-Key",
+		In:          "header",
+		Description: "Retries with the same key replay the first response.",
+		Schema:      &openapi.Schema{Type: "string"},
+	}}})
+	Spec.Use(middleware.ValidateSignedURL, openapi.Middleware{Parameters: []openapi.Parameter{
+		{Name: "expires", In: "query", Required: true, Schema: &openapi.Schema{Type: "integer", Format: "int64"}},
+		{Name: "signature", In: "query", Required: true, Schema: &openapi.Schema{Type: "string"}},
+	}})
+}
+
+// openapiSpec serves the OpenAPI document, built on the first request once
+// every route is registered.
+func openapiSpec(c *fiber.Ctx) error {
+	specOnce.Do(func() {
+		specJSON, _ = json.Marshal(Spec.Build(c.App().GetRoutes(true)))
+	})
+
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return c.Send(specJSON)
+}
+
+var swaggerUI = adaptor.HTTPHandler(v5emb.New(docsTitle, "/openapi.json", "/docs/"))
+
+// docsUI serves Swagger UI and its assets. It is named so its operation is
+// not matched by other adaptor.HTTPHandler routes such as /metrics.
+func docsUI(c *fiber.Ctx) error {
+	return swaggerUI(c)
+}
@@ -0,0 +1,53 @@
+package route
+
+import (
+	"codebase-app/internal/adapter"
+	"codebase-app/internal/infrastructure/config"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupApp(t *testing.T) *fiber.App {
+	t.Helper()
+
+	config.Envs = &config.Config{}
+	adapter.Adapters = &adapter.Adapter{}
+
+	app := fiber.New()
+	SetupRoutes(app)
+
+	return app
+}
+
+// TestSpecMatchesRoutes fails when a route is added without an operation in
+// its handler package's docs.go, or an operation is left for a removed route.
+func TestSpecMatchesRoutes(t *testing.T) {
+	app := setupApp(t)
+
+	undocumented, stale := Spec.Check(app.GetRoutes(true))
+	assert.Empty(t, undocumented, "routes without an operation")
+	assert.Empty(t, stale, "operations without a route")
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	app := setupApp(t)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/openapi.json", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var doc struct {
+		OpenAPI string                    `json:"openapi"`
+		Paths   map[string]map[string]any `json:"paths"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&doc))
+
+	assert.Equal(t, "3.1.0", doc.OpenAPI)
+	assert.Contains(t, doc.Paths["/products/{id}"], "patch")
+	assert.Contains(t, doc.Paths["/products/shops/{id}"], "get")
+}
@@ -0,0 +1,28 @@
+package route
+
+import (
+	"codebase-app/internal/infrastructure/config"
+	"codebase-app/pkg/response"
+	"path/filepath"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+// privateStorage serves files from the private local storage,
+// the route must be guarded by middleware.ValidateSignedURL.
+func privateStorage(c *fiber.Ctx) error {
+	// clean against a rooted path so "../" can never escape the storage directory
+	key := filepath.Clean("/" + c.Params("*"))
+	if key == "/" {
+		return c.Status(fiber.StatusNotFound).JSON(response.Error("File tidak ditemukan"))
+	}
+
+	fullpath := filepath.Join(config.Envs.App.LocalStoragePrivatePath, key)
+	if err := c.SendFile(fullpath); err != nil {
+		log.Ctx(c.UserContext()).Warn().Err(err).Str("key", key).Msg("route::privateStorage - Failed to send file")
+		return c.Status(fiber.StatusNotFound).JSON(response.Error("File tidak ditemukan"))
+	}
+
+	return nil
+}
@@ -15,6 +15,9 @@ type (
 	}
 )
 
+// Load reads the .env file and then each of the given files found in the
+// given paths into opts.Config, environment variables taking precedence.
+// A NAME_FILE variable sets NAME to the contents of that file.
 func Load(opts Opts) error {
 	for _, p := range opts.Paths {
 		fp := filepath.Join(p, ".env")
@@ -37,5 +40,14 @@ func Load(opts Opts) error {
 		}
 	}
 
+	// values from NAME_FILE override NAME, read the environment again
+	found, secretErr := readSecretFiles(opts.Config)
+	if secretErr != nil {
+		return secretErr
+	}
+	if found {
+		return cleanenv.ReadEnv(opts.Config)
+	}
+
 	return err
 }
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// Field is a config struct field read from an environment variable.
+type Field struct {
+	Env    string
+	Secret bool // tagged `secret:"true"`, hidden when redacting
+	Value  reflect.Value
+}
+
+// String formats the value the way it is written in the environment.
+func (f Field) String() string {
+	if f.Value.Kind() == reflect.Slice {
+		parts := make([]string, f.Value.Len())
+		for i := range parts {
+			parts[i] = fmt.Sprint(f.Value.Index(i).Interface())
+		}
+		return strings.Join(parts, ",")
+	}
+
+	return fmt.Sprint(f.Value.Interface())
+}
+
+// Fields lists every field of cfg that has an env tag, nested sections
+// included, in declaration order.
+func Fields(cfg any) []Field {
+	var fields []Field
+	collect(reflect.Indirect(reflect.ValueOf(cfg)), &fields)
+	return fields
+}
+
+func collect(v reflect.Value, fields *[]Field) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		if env := sf.Tag.Get("env"); env != "" {
+			*fields = append(*fields, Field{
+				Env:    strings.Split(env, ",")[0],
+				Secret: sf.Tag.Get("secret") == "true",
+				Value:  v.Field(i),
+			})
+			continue
+		}
+
+		if sf.Type.Kind() == reflect.Struct {
+			collect(v.Field(i), fields)
+		}
+	}
+}
+
+// Print writes cfg as NAME=value lines. With redact, secrets that are set
+// are printed as ******.
+func Print(w io.Writer, cfg any, redact bool) error {
+	for _, f := range Fields(cfg) {
+		val := f.String()
+		if redact && f.Secret && val != "" {
+			val = "******"
+		}
+
+		if _, err := fmt.Fprintf(w, "%s=%s\n", f.Env, val); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Diff returns the env names whose values differ between a and b, which
+// must be the same config type.
+func Diff(a, b any) []string {
+	var (
+		fa    = Fields(a)
+		fb    = Fields(b)
+		names []string
+	)
+
+	for i := range fa {
+		if !reflect.DeepEqual(fa[i].Value.Interface(), fb[i].Value.Interface()) {
+			names = append(names, fa[i].Env)
+		}
+	}
+
+	return names
+}
+
+// readSecretFiles sets NAME from the contents of the file in NAME_FILE for
+// every field whose NAME_FILE is set, so secrets mounted as files (docker
+// and kubernetes secrets) need not be put in the environment. It reports
+// whether any variable was set.
+func readSecretFiles(cfg any) (bool, error) {
+	var found bool
+	for _, f := range Fields(cfg) {
+		path := os.Getenv(f.Env + "_FILE")
+		if path == "" {
+			continue
+		}
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return false, fmt.Errorf("%s_FILE: %w", f.Env, err)
+		}
+
+		if err := os.Setenv(f.Env, strings.TrimRight(string(b), "\r\n")); err != nil {
+			return false, fmt.Errorf("%s_FILE: %w", f.Env, err)
+		}
+		found = true
+	}
+
+	return found, nil
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Duration is a time.Duration read from a config value such as "30s" or
+// "5m". A bare number is taken as seconds, the unit every duration setting
+// used before units were supported.
+type Duration time.Duration
+
+// SetValue implements cleanenv.Setter.
+func (d *Duration) SetValue(s string) error {
+	s = strings.TrimSpace(s)
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		*d = Duration(time.Duration(n) * time.Second)
+		return nil
+	}
+
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q, use a number of seconds or a value like 30s, 5m, 1h", s)
+	}
+
+	*d = Duration(v)
+	return nil
+}
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// ByteSize is a size in bytes read from a config value such as "4MB" or
+// "512KiB". Decimal (KB, MB, GB) and binary (KiB, MiB, GiB) units are
+// accepted, a bare number is taken as bytes.
+type ByteSize int64
+
+const (
+	KB ByteSize = 1000
+	MB          = 1000 * KB
+	GB          = 1000 * MB
+
+	KiB ByteSize = 1 << 10
+	MiB          = 1 << 20
+	GiB          = 1 << 30
+)
+
+// longest suffixes first so "MiB" is not read as "B"
+var byteUnits = []struct {
+	suffix string
+	size   ByteSize
+}{
+	{"KIB", KiB}, {"MIB", MiB}, {"GIB", GiB},
+	{"KB", KB}, {"MB", MB}, {"GB", GB},
+	{"B", 1},
+}
+
+// SetValue implements cleanenv.Setter.
+func (b *ByteSize) SetValue(s string) error {
+	raw := strings.ToUpper(strings.TrimSpace(s))
+
+	unit := ByteSize(1)
+	for _, u := range byteUnits {
+		if strings.HasSuffix(raw, u.suffix) {
+			raw = strings.TrimSpace(strings.TrimSuffix(raw, u.suffix))
+			unit = u.size
+			break
+		}
+	}
+
+	n, err := strconv.ParseFloat(raw, 64)
+	if err != nil || n < 0 {
+		return fmt.Errorf("invalid size %q, use a number of bytes or a value like 512KiB, 4MB", s)
+	}
+
+	*b = ByteSize(n * float64(unit))
+	return nil
+}
+
+func (b ByteSize) String() string {
+	for _, u := range []struct {
+		suffix string
+		size   ByteSize
+	}{{"GiB", GiB}, {"MiB", MiB}, {"KiB", KiB}, {"GB", GB}, {"MB", MB}, {"KB", KB}} {
+		if b >= u.size && b%u.size == 0 {
+			return strconv.FormatInt(int64(b/u.size), 10) + u.suffix
+		}
+	}
+
+	return strconv.FormatInt(int64(b), 10) + "B"
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// ValidationError lists every config value that failed validation.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate checks cfg against its `validate` struct tags. Problems are
+// reported by env name, e.g. "APP_ENV must be one of development staging
+// production, got \"prod\"".
+func Validate(cfg any) error {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		return strings.Split(fld.Tag.Get("env"), ",")[0]
+	})
+	if err := v.RegisterValidation("origins", isOrigins); err != nil {
+		return err
+	}
+
+	err := v.Struct(cfg)
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return err
+	}
+
+	verr := &ValidationError{}
+	for _, fe := range fieldErrs {
+		verr.Problems = append(verr.Problems, problem(fe))
+	}
+
+	return verr
+}
+
+func problem(fe validator.FieldError) string {
+	var (
+		name = fe.Field()
+		got  = fmt.Sprint(fe.Value())
+	)
+
+	// durations and sizes are compared as numbers, show the limit in their unit
+	param := fe.Param()
+	if s, ok := fe.Value().(fmt.Stringer); ok {
+		got = s.String()
+		if p := reflect.New(fe.Type()); param != "" {
+			if setter, ok := p.Interface().(interface{ SetValue(string) error }); ok && setter.SetValue(param) == nil {
+				param = fmt.Sprint(p.Elem().Interface())
+			}
+		}
+	}
+
+	switch fe.Tag() {
+	case "required":
+		return name + " is required"
+	case "ip|cidr":
+		return fmt.Sprintf("%s must be an ip or CIDR, got %q", name, got)
+	case "oneof":
+		return fmt.Sprintf("%s must be one of %s, got %q", name, param, got)
+	case "gt":
+		return fmt.Sprintf("%s must be greater than %s, got %s", name, param, got)
+	case "gte":
+		return fmt.Sprintf("%s must be at least %s, got %s", name, param, got)
+	case "lte":
+		return fmt.Sprintf("%s must be at most %s, got %s", name, param, got)
+	case "url", "http_url":
+		return fmt.Sprintf("%s must be a URL, got %q", name, got)
+	case "origins":
+		return fmt.Sprintf("%s must be * or comma separated origins like https://example.com, got %q", name, got)
+	case "numeric", "number":
+		return fmt.Sprintf("%s must be a number, got %q", name, got)
+	default:
+		return fmt.Sprintf("%s failed %s validation, got %q", name, fe.ActualTag(), got)
+	}
+}
+
+// isOrigins accepts "*" or a comma separated list of scheme://host[:port]
+// origins, the forms the CORS middleware accepts.
+func isOrigins(fl validator.FieldLevel) bool {
+	for _, origin := range strings.Split(fl.Field().String(), ",") {
+		origin = strings.TrimSpace(origin)
+		if origin == "*" {
+			continue
+		}
+
+		u, err := url.Parse(origin)
+		if err != nil || u.Scheme == "" || u.Host == "" || (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,70 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("codebase-app/pkg/database")
+
+// startSpan starts a client span for query, named after its SQL operation
+// (SELECT, INSERT, ...), under the request span of ctx.
+func startSpan(ctx context.Context, query string) (context.Context, trace.Span) {
+	op := operation(query)
+
+	return tracer.Start(ctx, op, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		semconv.DBSystemPostgreSQL,
+		semconv.DBOperationName(op),
+		semconv.DBQueryText(query),
+	))
+}
+
+// endSpan ends span, recording err unless it only means no rows matched.
+func endSpan(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// operation returns the first keyword of query, for a CTE the keyword of
+// its main statement.
+func operation(query string) string {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return "QUERY"
+	}
+
+	op := strings.ToUpper(fields[0])
+	if op != "WITH" {
+		return op
+	}
+
+	// the main statement follows the closing parenthesis of the last CTE
+	depth := 0
+	for i, r := range query {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				rest := strings.TrimSpace(query[i+1:])
+				if next := strings.Fields(rest); len(next) > 0 && !strings.HasPrefix(rest, ",") {
+					return strings.ToUpper(next[0])
+				}
+			}
+		}
+	}
+
+	return op
+}
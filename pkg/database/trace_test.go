@@ -0,0 +1,72 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var (
+	recorder     = tracetest.NewSpanRecorder()
+	recorderOnce sync.Once
+)
+
+// recordSpans returns the names of the spans ended by fn. The global
+// provider is set once, the package tracer only delegates to the first one.
+func recordSpans(fn func()) []string {
+	recorderOnce.Do(func() {
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	})
+
+	before := len(recorder.Ended())
+	fn()
+
+	names := make([]string, 0)
+	for _, span := range recorder.Ended()[before:] {
+		names = append(names, span.Name())
+	}
+
+	return names
+}
+
+func TestSelectNamedContextSpan(t *testing.T) {
+	db, mock := newMock(t)
+
+	mock.ExpectQuery(`SELECT id FROM products WHERE shop_id = \$1`).
+		WithArgs("s1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("p1"))
+
+	var (
+		ids []string
+		err error
+	)
+	spans := recordSpans(func() {
+		err = New(db).SelectNamedContext(context.Background(), &ids, "SELECT id FROM products WHERE shop_id = :shop_id", map[string]any{"shop_id": "s1"})
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"p1"}, ids)
+	assert.Equal(t, []string{"SELECT"}, spans)
+}
+
+func TestBeginSpan(t *testing.T) {
+	db, mock := newMock(t)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	var err error
+	spans := recordSpans(func() {
+		err = NewTxManager(db).WithTx(context.Background(), func(ctx context.Context) error { return nil })
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BEGIN"}, spans)
+}
@@ -0,0 +1,358 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// DBTX is what repositories query through, implemented by both *sqlx.DB
+// and *sqlx.Tx.
+type DBTX interface {
+	sqlx.ExtContext
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	GetContext(ctx context.Context, dest any, query string, args ...any) error
+	SelectContext(ctx context.Context, dest any, query string, args ...any) error
+	NamedExecContext(ctx context.Context, query string, arg any) (sql.Result, error)
+	PrepareNamedContext(ctx context.Context, query string) (*sqlx.NamedStmt, error)
+}
+
+var (
+	_ DBTX = &sqlx.DB{}
+	_ DBTX = &sqlx.Tx{}
+)
+
+type txKey struct{}
+
+// txState is the unit of work carried by a context.
+type txState struct {
+	tx          *sqlx.Tx
+	afterCommit []func()
+}
+
+func stateFrom(ctx context.Context) *txState {
+	state, _ := ctx.Value(txKey{}).(*txState)
+	return state
+}
+
+// AfterCommit runs fn once the transaction of ctx is committed, or right
+// away when ctx carries none. Use it for side effects that cannot be rolled
+// back, like removing files.
+func AfterCommit(ctx context.Context, fn func()) {
+	if state := stateFrom(ctx); state != nil {
+		state.afterCommit = append(state.afterCommit, fn)
+		return
+	}
+
+	fn()
+}
+
+// DB wraps the pool so every query runs on the transaction carried by the
+// context when there is one, repositories never have to know.
+type DB struct {
+	*sqlx.DB
+}
+
+func New(db *sqlx.DB) *DB {
+	return &DB{DB: db}
+}
+
+// Conn returns the transaction of ctx, or the pool.
+func (d *DB) Conn(ctx context.Context) DBTX {
+	if state := stateFrom(ctx); state != nil {
+		return state.tx
+	}
+
+	return d.DB
+}
+
+func (d *DB) ExecContext(ctx context.Context, query string, args ...any) (res sql.Result, err error) {
+	ctx, span := startSpan(ctx, query)
+	defer func() { endSpan(span, err) }()
+
+	return d.Conn(ctx).ExecContext(ctx, query, args...)
+}
+
+func (d *DB) QueryContext(ctx context.Context, query string, args ...any) (rows *sql.Rows, err error) {
+	ctx, span := startSpan(ctx, query)
+	defer func() { endSpan(span, err) }()
+
+	return d.Conn(ctx).QueryContext(ctx, query, args...)
+}
+
+func (d *DB) QueryxContext(ctx context.Context, query string, args ...any) (rows *sqlx.Rows, err error) {
+	ctx, span := startSpan(ctx, query)
+	defer func() { endSpan(span, err) }()
+
+	return d.Conn(ctx).QueryxContext(ctx, query, args...)
+}
+
+func (d *DB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	ctx, span := startSpan(ctx, query)
+
+	row := d.Conn(ctx).QueryRowContext(ctx, query, args...)
+	endSpan(span, row.Err())
+	return row
+}
+
+func (d *DB) QueryRowxContext(ctx context.Context, query string, args ...any) *sqlx.Row {
+	ctx, span := startSpan(ctx, query)
+
+	row := d.Conn(ctx).QueryRowxContext(ctx, query, args...)
+	endSpan(span, row.Err())
+	return row
+}
+
+func (d *DB) GetContext(ctx context.Context, dest any, query string, args ...any) (err error) {
+	ctx, span := startSpan(ctx, query)
+	defer func() { endSpan(span, err) }()
+
+	return d.Conn(ctx).GetContext(ctx, dest, query, args...)
+}
+
+func (d *DB) SelectContext(ctx context.Context, dest any, query string, args ...any) (err error) {
+	ctx, span := startSpan(ctx, query)
+	defer func() { endSpan(span, err) }()
+
+	return d.Conn(ctx).SelectContext(ctx, dest, query, args...)
+}
+
+func (d *DB) NamedExecContext(ctx context.Context, query string, arg any) (res sql.Result, err error) {
+	ctx, span := startSpan(ctx, query)
+	defer func() { endSpan(span, err) }()
+
+	return d.Conn(ctx).NamedExecContext(ctx, query, arg)
+}
+
+// SelectNamedContext is SelectContext with a named query, compiled here so
+// the query runs under a span like the others.
+func (d *DB) SelectNamedContext(ctx context.Context, dest any, query string, arg any) error {
+	query, args, err := d.BindNamed(query, arg)
+	if err != nil {
+		return err
+	}
+
+	return d.SelectContext(ctx, dest, query, args...)
+}
+
+// PrepareNamedContext prepares on the transaction of ctx, the statement runs
+// its queries outside any span; prefer SelectNamedContext.
+func (d *DB) PrepareNamedContext(ctx context.Context, query string) (*sqlx.NamedStmt, error) {
+	return d.Conn(ctx).PrepareNamedContext(ctx, query)
+}
+
+// beginTx starts a transaction under a BEGIN span.
+func beginTx(ctx context.Context, db *sqlx.DB, opts *sql.TxOptions) (tx *sqlx.Tx, err error) {
+	spanCtx, span := startSpan(ctx, "BEGIN")
+	defer func() { endSpan(span, err) }()
+
+	return db.BeginTxx(spanCtx, opts)
+}
+
+// Tx is a transaction started by Begin. When it joined the unit of work of
+// the context, Commit and Rollback are left to the owner of that unit.
+type Tx struct {
+	*sqlx.Tx
+	joined bool
+}
+
+// Begin starts a transaction for a repository method, or joins the one
+// already carried by ctx.
+func (d *DB) Begin(ctx context.Context) (*Tx, error) {
+	if state := stateFrom(ctx); state != nil {
+		return &Tx{Tx: state.tx, joined: true}, nil
+	}
+
+	tx, err := beginTx(ctx, d.DB, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tx{Tx: tx}, nil
+}
+
+func (t *Tx) ExecContext(ctx context.Context, query string, args ...any) (res sql.Result, err error) {
+	ctx, span := startSpan(ctx, query)
+	defer func() { endSpan(span, err) }()
+
+	return t.Tx.ExecContext(ctx, query, args...)
+}
+
+func (t *Tx) QueryxContext(ctx context.Context, query string, args ...any) (rows *sqlx.Rows, err error) {
+	ctx, span := startSpan(ctx, query)
+	defer func() { endSpan(span, err) }()
+
+	return t.Tx.QueryxContext(ctx, query, args...)
+}
+
+func (t *Tx) QueryRowxContext(ctx context.Context, query string, args ...any) *sqlx.Row {
+	ctx, span := startSpan(ctx, query)
+
+	row := t.Tx.QueryRowxContext(ctx, query, args...)
+	endSpan(span, row.Err())
+	return row
+}
+
+func (t *Tx) GetContext(ctx context.Context, dest any, query string, args ...any) (err error) {
+	ctx, span := startSpan(ctx, query)
+	defer func() { endSpan(span, err) }()
+
+	return t.Tx.GetContext(ctx, dest, query, args...)
+}
+
+func (t *Tx) SelectContext(ctx context.Context, dest any, query string, args ...any) (err error) {
+	ctx, span := startSpan(ctx, query)
+	defer func() { endSpan(span, err) }()
+
+	return t.Tx.SelectContext(ctx, dest, query, args...)
+}
+
+func (t *Tx) NamedExecContext(ctx context.Context, query string, arg any) (res sql.Result, err error) {
+	ctx, span := startSpan(ctx, query)
+	defer func() { endSpan(span, err) }()
+
+	return t.Tx.NamedExecContext(ctx, query, arg)
+}
+
+func (t *Tx) Commit() error {
+	if t.joined {
+		return nil
+	}
+
+	return t.Tx.Commit()
+}
+
+// Rollback of a joined transaction is a no-op, the error returned by the
+// repository makes the owner roll the whole unit back.
+func (t *Tx) Rollback() error {
+	if t.joined {
+		return nil
+	}
+
+	return t.Tx.Rollback()
+}
+
+// IsRetryable reports whether err is a serialization failure (40001) or a
+// deadlock (40P01), after which the whole transaction can be run again.
+func IsRetryable(err error) bool {
+	var errPq *pq.Error
+	if !errors.As(err, &errPq) {
+		return false
+	}
+
+	return errPq.Code == "40001" || errPq.Code == "40P01"
+}
+
+// TxManager runs units of work: several repository calls sharing one
+// transaction through the context.
+type TxManager struct {
+	db          *sqlx.DB
+	maxAttempts int
+	backoff     time.Duration
+}
+
+type Option func(*TxManager)
+
+// WithMaxAttempts sets how many times a unit of work is run when it keeps
+// failing with a retryable error, 3 by default.
+func WithMaxAttempts(n int) Option {
+	return func(m *TxManager) {
+		if n > 0 {
+			m.maxAttempts = n
+		}
+	}
+}
+
+// WithBackoff sets the wait before the first retry, it grows linearly.
+func WithBackoff(d time.Duration) Option {
+	return func(m *TxManager) {
+		m.backoff = d
+	}
+}
+
+func NewTxManager(db *sqlx.DB, opts ...Option) *TxManager {
+	m := &TxManager{
+		db:          db,
+		maxAttempts: 3,
+		backoff:     50 * time.Millisecond,
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// WithTx runs fn with a transaction on its context, committed when fn
+// returns nil. Inside a unit of work already, fn simply joins it. On a
+// serialization failure or deadlock the whole fn is run again, so it must
+// not have side effects outside the database (see AfterCommit).
+func (m *TxManager) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return m.WithTxOptions(ctx, nil, fn)
+}
+
+// WithTxOptions is WithTx with an isolation level or a read-only transaction.
+func (m *TxManager) WithTxOptions(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context) error) error {
+	if stateFrom(ctx) != nil {
+		return fn(ctx)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := m.run(ctx, opts, fn)
+		if err == nil || !IsRetryable(err) || attempt >= m.maxAttempts {
+			return err
+		}
+
+		log.Ctx(ctx).Warn().Err(err).Int("attempt", attempt).Msg("database::WithTx - Retrying transaction")
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt) * m.backoff):
+		}
+	}
+}
+
+func (m *TxManager) run(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context) error) (err error) {
+	tx, err := beginTx(ctx, m.db, opts)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("database::WithTx - Failed to begin transaction")
+		return err
+	}
+
+	state := &txState{tx: tx}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				log.Ctx(ctx).Error().Err(errRollback).Msg("database::WithTx - Failed to rollback transaction")
+			}
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, txKey{}, state)); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("database::WithTx - Failed to commit transaction")
+		return err
+	}
+
+	for _, hook := range state.afterCommit {
+		hook()
+	}
+
+	return nil
+}
@@ -0,0 +1,210 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const updateQuery = "UPDATE products SET stock = stock - 1"
+
+func newMock(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	return sqlx.NewDb(db, "postgres"), mock
+}
+
+func TestWithTxRetriesSerializationFailure(t *testing.T) {
+	db, mock := newMock(t)
+	serialization := &pq.Error{Code: "40001"}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(updateQuery).WillReturnError(serialization)
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectExec(updateQuery).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	attempts := 0
+	err := NewTxManager(db, WithBackoff(time.Millisecond)).WithTx(context.Background(), func(ctx context.Context) error {
+		attempts++
+		_, err := New(db).ExecContext(ctx, updateQuery)
+		return err
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithTxGivesUpAfterMaxAttempts(t *testing.T) {
+	db, mock := newMock(t)
+	deadlock := &pq.Error{Code: "40P01"}
+
+	for i := 0; i < 2; i++ {
+		mock.ExpectBegin()
+		mock.ExpectExec(updateQuery).WillReturnError(deadlock)
+		mock.ExpectRollback()
+	}
+
+	attempts := 0
+	err := NewTxManager(db, WithMaxAttempts(2), WithBackoff(time.Millisecond)).WithTx(context.Background(), func(ctx context.Context) error {
+		attempts++
+		_, err := New(db).ExecContext(ctx, updateQuery)
+		return err
+	})
+
+	assert.ErrorIs(t, err, deadlock)
+	assert.Equal(t, 2, attempts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithTxLogsWithRequestLogger(t *testing.T) {
+	db, mock := newMock(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(updateQuery).WillReturnError(&pq.Error{Code: "40001"})
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	var buf bytes.Buffer
+	ctx := zerolog.New(&buf).With().Str("request_id", "req-1").Logger().WithContext(context.Background())
+
+	attempts := 0
+	err := NewTxManager(db, WithBackoff(time.Millisecond)).WithTx(ctx, func(ctx context.Context) error {
+		attempts++
+		if attempts == 1 {
+			_, err := New(db).ExecContext(ctx, updateQuery)
+			return err
+		}
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), `"request_id":"req-1"`)
+	assert.Contains(t, buf.String(), "Retrying transaction")
+}
+
+func TestWithTxDoesNotRetryOtherErrors(t *testing.T) {
+	db, mock := newMock(t)
+	errFn := errors.New("out of stock")
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	attempts := 0
+	err := NewTxManager(db, WithBackoff(time.Millisecond)).WithTx(context.Background(), func(ctx context.Context) error {
+		attempts++
+		return errFn
+	})
+
+	assert.ErrorIs(t, err, errFn)
+	assert.Equal(t, 1, attempts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithTxJoinsOuterUnit(t *testing.T) {
+	db, mock := newMock(t)
+	m := NewTxManager(db)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	err := m.WithTx(context.Background(), func(ctx context.Context) error {
+		return m.WithTx(ctx, func(ctx context.Context) error { return nil })
+	})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAfterCommitRunsAfterCommit(t *testing.T) {
+	db, mock := newMock(t)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	ran := false
+	err := NewTxManager(db).WithTx(context.Background(), func(ctx context.Context) error {
+		AfterCommit(ctx, func() { ran = true })
+		assert.False(t, ran, "ran before commit")
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.True(t, ran)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAfterCommitSkippedWithoutCommit(t *testing.T) {
+	errFn := errors.New("out of stock")
+
+	tests := []struct {
+		name   string
+		expect func(mock sqlmock.Sqlmock)
+		fnErr  error
+	}{
+		{"rollback", func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectRollback()
+		}, errFn},
+		{"failed commit", func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectCommit().WillReturnError(errors.New("connection reset"))
+		}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newMock(t)
+			tt.expect(mock)
+
+			ran := false
+			err := NewTxManager(db).WithTx(context.Background(), func(ctx context.Context) error {
+				AfterCommit(ctx, func() { ran = true })
+				return tt.fnErr
+			})
+
+			assert.Error(t, err)
+			assert.False(t, ran)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAfterCommitWithoutTx(t *testing.T) {
+	ran := false
+	AfterCommit(context.Background(), func() { ran = true })
+
+	assert.True(t, ran)
+}
+
+func TestWithTxRollsBackOnPanic(t *testing.T) {
+	db, mock := newMock(t)
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	ran := false
+	assert.PanicsWithValue(t, "boom", func() {
+		_ = NewTxManager(db).WithTx(context.Background(), func(ctx context.Context) error {
+			AfterCommit(ctx, func() { ran = true })
+			panic("boom")
+		})
+	})
+
+	assert.False(t, ran)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
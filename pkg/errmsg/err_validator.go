@@ -57,6 +57,9 @@ func errorValidationHandler[T any](err error, payload *T) (int, map[string][]str
 		case "required":
 			// message = fmt.Sprintf("%s is required.", fieldInMsg)
 			message = fmt.Sprintf("%s harus diisi.", fieldInMsg)
+		case "required_with":
+			// message = fmt.Sprintf("%s is required when %s is present.", fieldInMsg, err.Param())
+			message = fmt.Sprintf("%s harus diisi jika %s diisi.", fieldInMsg, strings.ToLower(err.Param()))
 		case "email":
 			// message = fmt.Sprintf("%s is not a valid email address.", field)
 			message = fmt.Sprintf("%s bukan alamat email yang valid.", fieldInMsg)
@@ -78,6 +81,12 @@ func errorValidationHandler[T any](err error, payload *T) (int, map[string][]str
 		case "base64":
 			// message = fmt.Sprintf("%s is not a valid base64 format.", fieldInMsg)
 			message = fmt.Sprintf("%s bukan format base64 yang valid.", fieldInMsg)
+		case "base64_file":
+			// message = fmt.Sprintf("%s is not a valid base64 file.", fieldInMsg)
+			message = fmt.Sprintf("%s bukan file base64 yang valid.", fieldInMsg)
+		case "required_if":
+			// message = fmt.Sprintf("%s is required.", fieldInMsg)
+			message = fmt.Sprintf("%s harus diisi.", fieldInMsg)
 		case "base64url":
 			// message = fmt.Sprintf("%s is not a valid base64url format.", fieldInMsg)
 			message = fmt.Sprintf("%s bukan format base64url yang valid.", fieldInMsg)
@@ -122,6 +131,15 @@ func errorValidationHandler[T any](err error, payload *T) (int, map[string][]str
 		case "gte":
 			// message = fmt.Sprintf("%s must be greater than or equal to %s.", fieldInMsg, err.Param())
 			message = fmt.Sprintf("%s harus lebih dari atau sama dengan %s.", fieldInMsg, err.Param())
+		case "gtfield":
+			// message = fmt.Sprintf("%s must be greater than %s.", fieldInMsg, err.Param())
+			message = fmt.Sprintf("%s harus lebih dari %s.", fieldInMsg, strings.ToLower(err.Param()))
+		case "timezone":
+			// message = fmt.Sprintf("%s is not a valid timezone (Ex: Asia/Jakarta).", fieldInMsg)
+			message = fmt.Sprintf("%s bukan zona waktu yang valid (Contoh: Asia/Jakarta).", fieldInMsg)
+		case "unique":
+			// message = fmt.Sprintf("%s elements must be unique.", fieldInMsg)
+			message = fmt.Sprintf("elemen %s harus unik.", fieldInMsg)
 		case "lt":
 			// message = fmt.Sprintf("%s must be less than %s.", fieldInMsg, err.Param())
 			message = fmt.Sprintf("%s harus kurang dari %s.", fieldInMsg, err.Param())
@@ -171,9 +189,15 @@ func errorValidationHandler[T any](err error, payload *T) (int, map[string][]str
 			oneOfValues[len(oneOfValues)-1] = "atau " + oneOfValues[len(oneOfValues)-1]
 			oneOfValuesStr := strings.Join(oneOfValues, ", ")
 			message = fmt.Sprintf("%s harus salah satu dari %s.", fieldInMsg, oneOfValuesStr)
+		case "slug":
+			// message = fmt.Sprintf("%s must only contain lowercase letters, numbers and dashes.", fieldInMsg)
+			message = fmt.Sprintf("%s hanya boleh berisi huruf kecil, angka, dan tanda hubung.", fieldInMsg)
 		case "unique_in_slice":
 			// message = fmt.Sprintf("%s elements must be unique.", fieldInMsg)
 			message = fmt.Sprintf("elemen %s harus unik.", fieldInMsg)
+		case "iso4217":
+			// message = fmt.Sprintf("%s must be a valid ISO 4217 currency code.", fieldInMsg)
+			message = fmt.Sprintf("%s harus kode mata uang ISO 4217 yang valid (Contoh: IDR).", fieldInMsg)
 		}
 
 		errorMessages[field] = append(errorMessages[field], message)
@@ -0,0 +1,58 @@
+package pkg
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// FormatETag returns the strong ETag of a resource version, ex: "3".
+func FormatETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// FormatBodyETag returns the strong ETag of a representation, its version
+// followed by a hash of body, ex: "3-9f86d081884c7d65". It changes with the
+// body even when the version does not, and still works as an If-Match.
+func FormatBodyETag(version int, body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + strconv.Itoa(version) + "-" + hex.EncodeToString(sum[:8]) + `"`
+}
+
+// ParseETag returns the version of a strong ETag, ignoring the hash of a
+// FormatBodyETag. Weak ETags are rejected because If-Match always uses the
+// strong comparison.
+func ParseETag(etag string) (int, bool) {
+	etag = strings.TrimSpace(etag)
+	if len(etag) < 2 || etag[0] != '"' || etag[len(etag)-1] != '"' {
+		return 0, false
+	}
+
+	value := etag[1 : len(etag)-1]
+	if i := strings.IndexByte(value, '-'); i >= 0 {
+		value = value[:i]
+	}
+
+	version, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, false
+	}
+
+	return version, true
+}
+
+// ETagMatches reports whether an If-None-Match header matches etag. The
+// header may hold a list of ETags or "*", and uses the weak comparison.
+func ETagMatches(header string, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
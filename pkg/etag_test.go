@@ -0,0 +1,45 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseETag(t *testing.T) {
+	tests := []struct {
+		etag    string
+		version int
+		ok      bool
+	}{
+		{`"3"`, 3, true},
+		{FormatBodyETag(3, []byte(`{}`)), 3, true},
+		{`W/"3"`, 0, false},
+		{`"abc"`, 0, false},
+		{`3`, 0, false},
+	}
+
+	for _, tt := range tests {
+		version, ok := ParseETag(tt.etag)
+		assert.Equal(t, tt.ok, ok, tt.etag)
+		assert.Equal(t, tt.version, version, tt.etag)
+	}
+}
+
+func TestFormatBodyETag(t *testing.T) {
+	owner := FormatBodyETag(3, []byte(`{"status":"draft"}`))
+
+	assert.Equal(t, owner, FormatBodyETag(3, []byte(`{"status":"draft"}`)))
+	assert.NotEqual(t, owner, FormatBodyETag(3, []byte(`{"status":"active"}`)))
+	assert.NotEqual(t, owner, FormatBodyETag(4, []byte(`{"status":"draft"}`)))
+}
+
+func TestETagMatches(t *testing.T) {
+	etag := FormatBodyETag(3, []byte(`{}`))
+
+	assert.True(t, ETagMatches(etag, etag))
+	assert.True(t, ETagMatches(`"1", W/`+etag, etag))
+	assert.True(t, ETagMatches("*", etag))
+	assert.False(t, ETagMatches(`"3"`, etag))
+	assert.False(t, ETagMatches("", etag))
+}
@@ -11,7 +11,7 @@ import (
 func GenerateEphemeralToken(p CostumClaimsPayloadWs) (string, error) {
 	now := time.Now().UTC()
 	privateKey := []byte(config.Envs.Guard.JwtPrivateKeyWs)
-	exp := time.Now().Add(time.Second * time.Duration(config.Envs.Guard.JwtWsExp))
+	exp := time.Now().Add(time.Duration(config.Envs.Guard.JwtWsExp))
 
 	claims := CostumClaimsWs{
 		UserId: p.UserId,
@@ -0,0 +1,440 @@
+// Package migrator applies versioned SQL migrations, named
+// <version>_<name>.up.sql and <version>_<name>.down.sql, and records a
+// checksum of each applied one so later edits of its file are detected.
+package migrator
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+// lockKey is the Postgres advisory lock held while migrating, so replicas
+// started together never run the same migration twice.
+const lockKey int64 = 7_265_021_438_104_911
+
+// historyTable records the applied migrations. Databases migrated by the
+// migrate CLI before are picked up from its schema_migrations table.
+const historyTable = "schema_migration_history"
+
+var (
+	ErrChecksumMismatch = errors.New("migration changed after it was applied")
+	ErrMissing          = errors.New("applied migration not found")
+)
+
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+// Status of a migration, AppliedAt is nil while it is pending.
+type Status struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+	Modified  bool // its up file changed after it was applied
+	Missing   bool // applied, but its files are gone
+}
+
+type Migrator struct {
+	db         *sqlx.DB
+	migrations []Migration
+}
+
+// New reads the migrations of fsys, sorted by version.
+func New(db *sqlx.DB, fsys fs.FS) (*Migrator, error) {
+	files, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+
+	for _, file := range files {
+		base := path.Base(file)
+
+		var (
+			up   = strings.HasSuffix(base, ".up.sql")
+			down = strings.HasSuffix(base, ".down.sql")
+		)
+		if !up && !down {
+			return nil, fmt.Errorf("migrator: %s is neither an up nor a down migration", base)
+		}
+
+		name := strings.TrimSuffix(strings.TrimSuffix(base, ".up.sql"), ".down.sql")
+		rawVersion, name, _ := strings.Cut(name, "_")
+
+		version, err := strconv.ParseInt(rawVersion, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migrator: invalid version in %s", base)
+		}
+
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+
+		if up {
+			sum := sha256.Sum256(content)
+			m.Up = string(content)
+			m.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Checksum == "" {
+			return nil, fmt.Errorf("migrator: migration %d has no up file", m.Version)
+		}
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+type applied struct {
+	Version   int64     `db:"version"`
+	Checksum  string    `db:"checksum"`
+	AppliedAt time.Time `db:"applied_at"`
+}
+
+// withLock runs fn on a single connection holding the advisory lock, after
+// the history table is set up.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sqlx.Conn) error) (err error) {
+	conn, err := m.db.Connx(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err = conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockKey); err != nil {
+		log.Error().Err(err).Msg("migrator::withLock - Failed to acquire advisory lock")
+		return err
+	}
+	defer func() {
+		// the lock is released with the session anyway if this fails
+		if _, errUnlock := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockKey); errUnlock != nil {
+			log.Warn().Err(errUnlock).Msg("migrator::withLock - Failed to release advisory lock")
+		}
+	}()
+
+	if err = m.setup(ctx, conn); err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
+
+// setup creates the history table. On a database migrated by the migrate
+// CLI, the migrations up to its version are recorded as applied.
+func (m *Migrator) setup(ctx context.Context, conn *sqlx.Conn) error {
+	var exists bool
+	err := conn.GetContext(ctx, &exists, "SELECT to_regclass($1) IS NOT NULL", historyTable)
+	if err != nil {
+		log.Error().Err(err).Msg("migrator::setup - Failed to look up history table")
+		return err
+	}
+
+	if exists {
+		return nil
+	}
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		CREATE TABLE `+historyTable+` (
+			version BIGINT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			checksum CHAR(64) NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT NOW()
+		)
+	`)
+	if err != nil {
+		log.Error().Err(err).Msg("migrator::setup - Failed to create history table")
+		return err
+	}
+
+	var legacy bool
+	if err = tx.GetContext(ctx, &legacy, "SELECT to_regclass('schema_migrations') IS NOT NULL"); err != nil {
+		return err
+	}
+
+	if legacy {
+		var (
+			version int64
+			dirty   bool
+		)
+
+		err = tx.QueryRowxContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			log.Error().Err(err).Msg("migrator::setup - Failed to read schema_migrations")
+			return err
+		}
+
+		if dirty {
+			return fmt.Errorf("migrator: schema_migrations is dirty at version %d, fix the database and run migrate force", version)
+		}
+
+		for _, mig := range m.migrations {
+			if mig.Version > version {
+				break
+			}
+			if err = record(ctx, tx, mig); err != nil {
+				return err
+			}
+		}
+
+		log.Info().Int64("version", version).Msg("migrator::setup - Adopted migrations applied by the migrate CLI")
+	}
+
+	return tx.Commit()
+}
+
+func record(ctx context.Context, tx *sqlx.Tx, mig Migration) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO `+historyTable+` (version, name, checksum)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (version) DO UPDATE SET name = EXCLUDED.name, checksum = EXCLUDED.checksum
+	`, mig.Version, mig.Name, mig.Checksum)
+	if err != nil {
+		log.Error().Err(err).Int64("version", mig.Version).Msg("migrator::record - Failed to record migration")
+	}
+
+	return err
+}
+
+func (m *Migrator) applied(ctx context.Context, conn *sqlx.Conn) (map[int64]applied, error) {
+	var rows []applied
+
+	err := conn.SelectContext(ctx, &rows, "SELECT version, checksum, applied_at FROM "+historyTable)
+	if err != nil {
+		log.Error().Err(err).Msg("migrator::applied - Failed to get applied migrations")
+		return nil, err
+	}
+
+	result := make(map[int64]applied, len(rows))
+	for _, row := range rows {
+		result[row.Version] = row
+	}
+
+	return result, nil
+}
+
+// verify fails when an applied migration was edited or removed.
+func (m *Migrator) verify(done map[int64]applied) error {
+	known := make(map[int64]bool, len(m.migrations))
+
+	for _, mig := range m.migrations {
+		known[mig.Version] = true
+
+		if a, ok := done[mig.Version]; ok && a.Checksum != mig.Checksum {
+			return fmt.Errorf("%w: %d_%s", ErrChecksumMismatch, mig.Version, mig.Name)
+		}
+	}
+
+	for version := range done {
+		if !known[version] {
+			return fmt.Errorf("%w: %d", ErrMissing, version)
+		}
+	}
+
+	return nil
+}
+
+// run executes one migration and updates the history in the same
+// transaction, so a failed migration leaves nothing behind.
+func run(ctx context.Context, conn *sqlx.Conn, mig Migration, up bool) (err error) {
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	script := mig.Down
+	if up {
+		script = mig.Up
+	}
+
+	if strings.TrimSpace(script) != "" {
+		if _, err = tx.ExecContext(ctx, script); err != nil {
+			return fmt.Errorf("migration %d_%s: %w", mig.Version, mig.Name, err)
+		}
+	}
+
+	if up {
+		err = record(ctx, tx, mig)
+	} else {
+		_, err = tx.ExecContext(ctx, "DELETE FROM "+historyTable+" WHERE version = $1", mig.Version)
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Up applies up to steps pending migrations, all of them when steps is 0,
+// and returns how many ran.
+func (m *Migrator) Up(ctx context.Context, steps int) (count int, err error) {
+	err = m.withLock(ctx, func(conn *sqlx.Conn) error {
+		done, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		if err = m.verify(done); err != nil {
+			return err
+		}
+
+		for _, mig := range m.migrations {
+			if _, ok := done[mig.Version]; ok {
+				continue
+			}
+			if steps > 0 && count == steps {
+				break
+			}
+
+			if err = run(ctx, conn, mig, true); err != nil {
+				return err
+			}
+
+			count++
+			log.Info().Int64("version", mig.Version).Str("name", mig.Name).Msg("Migration applied")
+		}
+
+		return nil
+	})
+
+	return count, err
+}
+
+// Down reverts the last steps applied migrations and returns how many ran.
+func (m *Migrator) Down(ctx context.Context, steps int) (count int, err error) {
+	err = m.withLock(ctx, func(conn *sqlx.Conn) error {
+		done, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		if err = m.verify(done); err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && count < steps; i-- {
+			mig := m.migrations[i]
+			if _, ok := done[mig.Version]; !ok {
+				continue
+			}
+
+			if err = run(ctx, conn, mig, false); err != nil {
+				return err
+			}
+
+			count++
+			log.Info().Int64("version", mig.Version).Str("name", mig.Name).Msg("Migration reverted")
+		}
+
+		return nil
+	})
+
+	return count, err
+}
+
+// Status lists every migration, plus applied ones whose files are gone.
+func (m *Migrator) Status(ctx context.Context) (statuses []Status, err error) {
+	err = m.withLock(ctx, func(conn *sqlx.Conn) error {
+		done, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, mig := range m.migrations {
+			s := Status{Version: mig.Version, Name: mig.Name}
+			if a, ok := done[mig.Version]; ok {
+				s.AppliedAt = &a.AppliedAt
+				s.Modified = a.Checksum != mig.Checksum
+				delete(done, mig.Version)
+			}
+			statuses = append(statuses, s)
+		}
+
+		for _, a := range done {
+			statuses = append(statuses, Status{Version: a.Version, AppliedAt: &a.AppliedAt, Missing: true})
+		}
+
+		sort.Slice(statuses, func(i, j int) bool {
+			return statuses[i].Version < statuses[j].Version
+		})
+
+		return nil
+	})
+
+	return statuses, err
+}
+
+// Force records the database as migrated exactly up to version without
+// running anything, and accepts the current checksums of those migrations.
+// Use it after fixing a database by hand.
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	return m.withLock(ctx, func(conn *sqlx.Conn) (err error) {
+		tx, err := conn.BeginTxx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err != nil {
+				_ = tx.Rollback()
+			}
+		}()
+
+		if _, err = tx.ExecContext(ctx, "DELETE FROM "+historyTable+" WHERE version > $1", version); err != nil {
+			return err
+		}
+
+		for _, mig := range m.migrations {
+			if mig.Version > version {
+				break
+			}
+			if err = record(ctx, tx, mig); err != nil {
+				return err
+			}
+		}
+
+		return tx.Commit()
+	})
+}
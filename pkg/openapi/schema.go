@@ -0,0 +1,367 @@
+package openapi
+
+import (
+	"encoding"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	decimalType       = reflect.TypeOf(decimal.Decimal{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+	// characters not allowed in a component name
+	invalidName = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+)
+
+// patch is implemented by types.Patch, a PATCH member that may be null.
+type patch interface {
+	IsNull() bool
+	HasValue() bool
+}
+
+var patchType = reflect.TypeOf((*patch)(nil)).Elem()
+
+// generator builds schemas from Go types the way encoding/json and the
+// validator read them. Named structs become components.
+type generator struct {
+	schemas map[string]*Schema
+}
+
+func (g *generator) schema(t reflect.Type) *Schema {
+	if t.Kind() == reflect.Pointer {
+		return nullable(g.schema(t.Elem()))
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == decimalType:
+		// shopspring/decimal marshals to a quoted string
+		return &Schema{Type: "string", Format: "decimal", Pattern: `^-?\d+(\.\d+)?$`}
+	case t.Kind() == reflect.Struct && t.Implements(patchType):
+		if f, ok := t.FieldByName("Value"); ok {
+			return nullable(g.schema(f.Type))
+		}
+	case t.Implements(textMarshalerType):
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Array:
+		return &Schema{Type: "array", Items: g.schema(t.Elem()), MinItems: ptr(t.Len()), MaxItems: ptr(t.Len())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+
+		name := schemaName(t)
+		if _, ok := g.schemas[name]; !ok {
+			// reserved first so recursive types refer to themselves
+			g.schemas[name] = nil
+			g.schemas[name] = g.object(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+
+	// interfaces and anything else accept any value
+	return &Schema{}
+}
+
+func (g *generator) object(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	g.fields(t, s)
+	return s
+}
+
+// fields adds the json tagged fields of t to s, embedded structs are
+// flattened like encoding/json does. Untagged fields are internal (set by
+// the handler from locals or params) and left out.
+func (g *generator) fields(t reflect.Type, s *Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := tagName(f, "json")
+
+		if f.Anonymous && name == "" {
+			if ft := deref(f.Type); ft.Kind() == reflect.Struct {
+				g.fields(ft, s)
+			}
+			continue
+		}
+
+		if !f.IsExported() || name == "" {
+			continue
+		}
+
+		fs := g.schema(f.Type)
+		if constrain(fs, f.Tag.Get("validate")) {
+			s.Required = append(s.Required, name)
+		}
+		s.Properties[name] = fs
+	}
+}
+
+// parameters lists the fields of t tagged with in ("query"), embedded
+// structs included.
+func (g *generator) parameters(t reflect.Type, in string) []Parameter {
+	var params []Parameter
+	seen := map[string]bool{}
+
+	var walk func(t reflect.Type)
+	walk = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.Anonymous && f.Tag.Get(in) == "" {
+				if ft := deref(f.Type); ft.Kind() == reflect.Struct {
+					walk(ft)
+				}
+				continue
+			}
+
+			name := tagName(f, in)
+			if !f.IsExported() || name == "" || seen[name] {
+				continue
+			}
+			seen[name] = true
+
+			p := Parameter{Name: name, In: in, Schema: g.schema(f.Type)}
+			p.Required = constrain(p.Schema, f.Tag.Get("validate"))
+			params = append(params, p)
+		}
+	}
+	walk(t)
+
+	return params
+}
+
+// pathParameter describes the path parameter name, typed by the field of t
+// it is read into: tagged params, or else db or json, with that name.
+func (g *generator) pathParameter(t reflect.Type, name string) Parameter {
+	p := Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}}
+	if t == nil {
+		return p
+	}
+
+	for _, tag := range []string{"params", "db", "json"} {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if tagName(f, tag) == name {
+				p.Schema = g.schema(f.Type)
+				constrain(p.Schema, f.Tag.Get("validate"))
+				return p
+			}
+		}
+	}
+
+	return p
+}
+
+// constrain applies the validator rules in tag to s and reports whether
+// the value is required.
+func constrain(s *Schema, tag string) (required bool) {
+	if s.Ref != "" {
+		return strings.Contains(","+tag+",", ",required,")
+	}
+	if len(s.OneOf) > 0 && s.OneOf[0].Ref == "" {
+		s = s.OneOf[0]
+	}
+
+	rules := strings.Split(tag, ",")
+	for i, rule := range rules {
+		if rule == "dive" {
+			if s.Items != nil {
+				constrain(s.Items, strings.Join(rules[i+1:], ","))
+			}
+			return required
+		}
+
+		// alternatives ("a|b") cannot be expressed per keyword
+		if strings.Contains(rule, "|") {
+			continue
+		}
+
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			required = true
+		case "uuid", "uuid4":
+			s.Format = "uuid"
+		case "email":
+			s.Format = "email"
+		case "url", "http_url", "uri":
+			s.Format = "uri"
+		case "iso4217":
+			s.Pattern = "^[A-Z]{3}$"
+		case "numeric", "number":
+			if kind(s) == "string" {
+				s.Pattern = `^-?\d+(\.\d+)?$`
+			}
+		case "latitude":
+			s.Minimum, s.Maximum = ptr(-90.0), ptr(90.0)
+		case "longitude":
+			s.Minimum, s.Maximum = ptr(-180.0), ptr(180.0)
+		case "oneof":
+			for _, v := range strings.Fields(param) {
+				if n, err := strconv.ParseFloat(v, 64); err == nil && kind(s) != "string" {
+					s.Enum = append(s.Enum, n)
+				} else {
+					s.Enum = append(s.Enum, v)
+				}
+			}
+		case "min", "max", "len", "gt", "gte", "lt", "lte":
+			bound(s, name, param)
+		}
+	}
+
+	return required
+}
+
+// bound sets a length, item count or value limit depending on the type.
+func bound(s *Schema, rule, param string) {
+	n, err := strconv.ParseFloat(param, 64)
+	// decimals are strings, their value bounds cannot be expressed
+	if err != nil || s.Format == "decimal" {
+		return
+	}
+
+	switch kind(s) {
+	case "string", "array", "object":
+		count := int(n)
+		switch rule {
+		case "gt":
+			count++
+		case "lt":
+			count--
+		}
+
+		var min, max **int
+		switch kind(s) {
+		case "string":
+			min, max = &s.MinLength, &s.MaxLength
+		case "array":
+			min, max = &s.MinItems, &s.MaxItems
+		default:
+			return
+		}
+
+		switch rule {
+		case "min", "gt", "gte":
+			*min = ptr(count)
+		case "max", "lt", "lte":
+			*max = ptr(count)
+		case "len":
+			*min, *max = ptr(count), ptr(count)
+		}
+	case "integer", "number":
+		switch rule {
+		case "min", "gte":
+			s.Minimum = ptr(n)
+		case "max", "lte":
+			s.Maximum = ptr(n)
+		case "gt":
+			s.ExclusiveMinimum = ptr(n)
+		case "lt":
+			s.ExclusiveMaximum = ptr(n)
+		case "len":
+			s.Minimum, s.Maximum = ptr(n), ptr(n)
+		}
+	}
+}
+
+// nullable allows null besides the values of s.
+func nullable(s *Schema) *Schema {
+	if t, ok := s.Type.(string); ok && s.Ref == "" {
+		c := *s
+		c.Type = []string{t, "null"}
+		return &c
+	}
+	if _, ok := s.Type.([]string); ok {
+		return s
+	}
+
+	return &Schema{OneOf: []*Schema{s, {Type: "null"}}}
+}
+
+// kind is the non-null type of s.
+func kind(s *Schema) string {
+	switch t := s.Type.(type) {
+	case string:
+		return t
+	case []string:
+		return t[0]
+	}
+
+	return ""
+}
+
+// schemaName names a component after the module and type, e.g.
+// "products.CreateProductRequest" for .../module/products/entity.
+func schemaName(t reflect.Type) string {
+	parts := strings.Split(t.PkgPath(), "/")
+	pkg := parts[len(parts)-1]
+	if pkg == "entity" && len(parts) > 1 {
+		pkg = parts[len(parts)-2]
+	}
+
+	return invalidName.ReplaceAllString(pkg+"."+t.Name(), "_")
+}
+
+func tagName(f reflect.StructField, tag string) string {
+	name, _, _ := strings.Cut(f.Tag.Get(tag), ",")
+	if name == "-" {
+		return ""
+	}
+
+	return name
+}
+
+func hasJSONFields(t reflect.Type) bool {
+	t = deref(t)
+	if t.Kind() != reflect.Struct {
+		return true
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if tagName(f, "json") != "" || (f.Anonymous && hasJSONFields(f.Type)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func deref(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,302 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Operation documents the handler of a route. Routes are matched to their
+// operation by handler, so the spec follows the routes as registered.
+type Operation struct {
+	// Handler is the method expression of the handler, e.g.
+	// (*productHandler).CreateProduct.
+	Handler     any
+	Summary     string
+	Description string
+	// Tags default to the module the handler is in.
+	Tags []string
+	// Request is read for path and query parameters and, for POST, PUT
+	// and PATCH, the JSON body.
+	Request any
+	// Response is the data of the success envelope, nil when it is null.
+	Response any
+	// Raw responses are Response itself instead of the success envelope.
+	Raw bool
+	// ContentType of a response that is not JSON, e.g. a file.
+	ContentType string
+	// Status defaults to 200.
+	Status int
+}
+
+// Middleware documents what a middleware in front of a handler requires.
+type Middleware struct {
+	Parameters []Parameter
+	// Security names a security scheme of the document, e.g. "bearer".
+	Security string
+}
+
+// Spec builds the document of an app from the described operations.
+type Spec struct {
+	info        Info
+	operations  map[string]Operation
+	middlewares map[string]Middleware
+}
+
+func New(info Info) *Spec {
+	return &Spec{
+		info:        info,
+		operations:  make(map[string]Operation),
+		middlewares: make(map[string]Middleware),
+	}
+}
+
+// Describe adds operations, a later one for the same handler wins.
+func (s *Spec) Describe(ops ...Operation) {
+	for _, op := range ops {
+		s.operations[funcName(op.Handler)] = op
+	}
+}
+
+// Use documents a middleware. Middlewares built by a factory, such as
+// middleware.Idempotency(), are matched by the closure they return.
+func (s *Spec) Use(mw fiber.Handler, m Middleware) {
+	s.middlewares[funcName(mw)] = m
+}
+
+// Check compares routes with the described operations. Undocumented are
+// the routes without an operation, stale the operations no route uses.
+func (s *Spec) Check(routes []fiber.Route) (undocumented, stale []string) {
+	used := make(map[string]bool)
+	for _, r := range documentable(routes) {
+		name := funcName(r.Handlers[len(r.Handlers)-1])
+		if _, ok := s.operations[name]; !ok {
+			undocumented = append(undocumented, r.Method+" "+r.Path+" ("+name+")")
+		}
+		used[name] = true
+	}
+
+	for name := range s.operations {
+		if !used[name] {
+			stale = append(stale, name)
+		}
+	}
+	sort.Strings(stale)
+
+	return undocumented, stale
+}
+
+// Build documents routes, routes without an operation get their path and
+// parameters only.
+func (s *Spec) Build(routes []fiber.Route) *Document {
+	var (
+		g   = &generator{schemas: make(map[string]*Schema)}
+		doc = &Document{
+			OpenAPI: "3.1.0",
+			Info:    s.info,
+			Paths:   make(map[string]*PathItem),
+			Components: Components{
+				Schemas: g.schemas,
+				SecuritySchemes: map[string]*SecurityScheme{
+					"bearer": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+				},
+			},
+		}
+	)
+
+	g.schemas["Error"] = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"success":  {Type: "boolean"},
+			"message":  {Type: "string"},
+			"errors":   {Type: "object", AdditionalProperties: &Schema{Type: "array", Items: &Schema{Type: "string"}}},
+			"trace_id": {Type: "string"},
+		},
+		Required: []string{"success", "message"},
+	}
+
+	for _, r := range documentable(routes) {
+		name := funcName(r.Handlers[len(r.Handlers)-1])
+		op := s.operations[name]
+
+		path := oasPath(r.Path)
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = &PathItem{}
+			doc.Paths[path] = item
+		}
+		(*item)[strings.ToLower(r.Method)] = s.operation(g, r, name, op)
+	}
+
+	return doc
+}
+
+func (s *Spec) operation(g *generator, r fiber.Route, name string, op Operation) *OperationObject {
+	o := &OperationObject{
+		OperationId: operationId(name, r),
+		Summary:     op.Summary,
+		Description: op.Description,
+		Tags:        op.Tags,
+		Responses:   map[string]*Response{},
+	}
+	if len(o.Tags) == 0 {
+		o.Tags = []string{module(name)}
+	}
+
+	var req reflect.Type
+	if op.Request != nil {
+		req = deref(reflect.TypeOf(op.Request))
+	}
+
+	for _, p := range r.Params {
+		o.Parameters = append(o.Parameters, g.pathParameter(req, paramName(p)))
+	}
+
+	for _, h := range r.Handlers[:len(r.Handlers)-1] {
+		mw, ok := s.middlewares[funcName(h)]
+		if !ok {
+			continue
+		}
+		o.Parameters = append(o.Parameters, mw.Parameters...)
+		if mw.Security != "" {
+			o.Security = append(o.Security, map[string][]string{mw.Security: {}})
+		}
+	}
+
+	if req != nil && req.Kind() == reflect.Struct {
+		switch r.Method {
+		case fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch:
+			if hasJSONFields(req) {
+				o.RequestBody = &RequestBody{
+					Required: true,
+					Content:  map[string]*MediaType{fiber.MIMEApplicationJSON: {Schema: g.schema(req)}},
+				}
+			}
+		default:
+			o.Parameters = append(o.Parameters, g.parameters(req, "query")...)
+		}
+	}
+
+	status := op.Status
+	if status == 0 {
+		status = fiber.StatusOK
+	}
+
+	var body *Schema
+	switch {
+	case op.ContentType != "":
+		body = &Schema{Type: "string", Format: "binary"}
+	case op.Raw && op.Response == nil:
+		body = &Schema{}
+	case op.Raw:
+		body = g.schema(reflect.TypeOf(op.Response))
+	default:
+		data := &Schema{Type: "null"}
+		if op.Response != nil {
+			data = g.schema(reflect.TypeOf(op.Response))
+		}
+		body = &Schema{
+			Type: "object",
+			Properties: map[string]*Schema{
+				"success": {Type: "boolean"},
+				"message": {Type: "string"},
+				"data":    data,
+			},
+			Required: []string{"success", "message", "data"},
+		}
+	}
+
+	contentType := op.ContentType
+	if contentType == "" {
+		contentType = fiber.MIMEApplicationJSON
+	}
+
+	o.Responses[strconv.Itoa(status)] = &Response{
+		Description: http.StatusText(status),
+		Content:     map[string]*MediaType{contentType: {Schema: body}},
+	}
+
+	errorBody := map[string]*MediaType{fiber.MIMEApplicationJSON: {Schema: &Schema{Ref: "#/components/schemas/Error"}}}
+	o.Responses["4XX"] = &Response{Description: "Invalid request", Content: errorBody}
+	o.Responses["5XX"] = &Response{Description: "Server error", Content: errorBody}
+
+	return o
+}
+
+// documentable leaves out the HEAD routes fiber adds for every GET.
+func documentable(routes []fiber.Route) []fiber.Route {
+	var out []fiber.Route
+	for _, r := range routes {
+		if r.Method == fiber.MethodHead || len(r.Handlers) == 0 {
+			continue
+		}
+		out = append(out, r)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Path != out[j].Path {
+			return out[i].Path < out[j].Path
+		}
+		return out[i].Method < out[j].Method
+	})
+
+	return out
+}
+
+// funcName names a function; method values (h.GetProduct) and method
+// expressions ((*handler).GetProduct) get the same name.
+func funcName(fn any) string {
+	name := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name()
+	return strings.TrimSuffix(name, "-fm")
+}
+
+// module is the module directory of a handler, e.g. "products" for
+// codebase-app/internal/module/products/handler/rest.(*productHandler).GetProduct.
+func module(name string) string {
+	if _, rest, ok := strings.Cut(name, "/module/"); ok {
+		m, _, _ := strings.Cut(rest, "/")
+		return m
+	}
+
+	return "system"
+}
+
+func operationId(name string, r fiber.Route) string {
+	i := strings.LastIndex(name, ".")
+	if i < 0 || strings.Contains(name[i:], "func") {
+		return strings.ToLower(r.Method) + strings.ReplaceAll(oasPath(r.Path), "/", "_")
+	}
+
+	return module(name) + "." + name[i+1:]
+}
+
+// oasPath turns fiber's /products/:id into /products/{id}, wildcards
+// become {path}.
+func oasPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		switch {
+		case strings.HasPrefix(seg, ":"):
+			segments[i] = "{" + paramName(seg[1:]) + "}"
+		case seg == "*" || seg == "+":
+			segments[i] = "{path}"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// paramName strips fiber's optional marker and constraints, and names
+// wildcards path.
+func paramName(p string) string {
+	if strings.HasPrefix(p, "*") || strings.HasPrefix(p, "+") {
+		return "path"
+	}
+	p, _, _ = strings.Cut(p, "<")
+	return strings.TrimSuffix(p, "?")
+}
@@ -0,0 +1,41 @@
+package pkg
+
+import (
+	"strings"
+	"unicode"
+)
+
+// MaxSlugLength is the longest slug the validator accepts.
+const MaxSlugLength = 100
+
+// Slugify lowercases s and joins its alphanumeric runs with "-",
+// ex: "Toko Baju  Murah!" => "toko-baju-murah". The result is cut to
+// MaxSlugLength and never starts or ends with "-".
+func Slugify(s string) string {
+	var (
+		b       strings.Builder
+		pending bool
+	)
+
+	for _, r := range strings.ToLower(strings.TrimSpace(s)) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if pending && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			pending = false
+			continue
+		}
+
+		if unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r) {
+			pending = true
+		}
+	}
+
+	slug := b.String()
+	if len(slug) > MaxSlugLength {
+		slug = slug[:MaxSlugLength]
+	}
+
+	return strings.Trim(slug, "-")
+}
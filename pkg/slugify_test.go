@@ -0,0 +1,27 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Toko Baju  Murah!", "toko-baju-murah"},
+		{"  --Toko-- ", "toko"},
+		{"!!!", ""},
+		{strings.Repeat("a", 99) + " bcd", strings.Repeat("a", 99)},
+		{strings.Repeat("ab ", 60), strings.Repeat("ab-", 33) + "a"},
+		{strings.Repeat("a", 99) + "-b", strings.Repeat("a", 99)},
+	}
+
+	for _, tt := range tests {
+		got := Slugify(tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+		assert.LessOrEqual(t, len(got), MaxSlugLength)
+	}
+}
@@ -0,0 +1,68 @@
+package types
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// DefaultCurrency is the ISO 4217 code used when none is given.
+const DefaultCurrency = "IDR"
+
+// Money is an exact decimal amount in an ISO 4217 currency.
+//
+// In JSON the amount is always a string, e.g. {"amount":"125000.5","currency":"IDR"},
+// so clients never round it through a float. In SQL it maps to a record of
+// (amount, currency), select it with ROW(price, currency).
+type Money struct {
+	Amount   decimal.Decimal `json:"amount"`
+	Currency string          `json:"currency"`
+}
+
+// NewMoney builds a Money from an amount and a currency code.
+func NewMoney(amount decimal.Decimal, currency string) Money {
+	return Money{
+		Amount:   amount,
+		Currency: strings.ToUpper(currency),
+	}
+}
+
+func (m Money) String() string {
+	return m.Amount.String() + " " + m.Currency
+}
+
+// Scan implements the sql.Scanner interface, it reads a record in the
+// "(125000.0000,IDR)" text form.
+func (m *Money) Scan(val interface{}) error {
+	var raw string
+	switch v := val.(type) {
+	case []uint8:
+		raw = string(v)
+	case string:
+		raw = v
+	default:
+		return fmt.Errorf("unsupported money type %T", val)
+	}
+
+	raw = strings.TrimSuffix(strings.TrimPrefix(raw, "("), ")")
+	amount, currency, ok := strings.Cut(raw, ",")
+	if !ok {
+		return fmt.Errorf("invalid money %q", raw)
+	}
+
+	d, err := decimal.NewFromString(amount)
+	if err != nil {
+		return err
+	}
+
+	*m = NewMoney(d, strings.TrimSpace(strings.Trim(currency, `"`)))
+	return nil
+}
+
+// Value implements the driver.Valuer interface, it writes the same record
+// text form read by Scan.
+func (m Money) Value() (driver.Value, error) {
+	return fmt.Sprintf("(%s,%s)", m.Amount.String(), m.Currency), nil
+}
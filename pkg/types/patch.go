@@ -0,0 +1,113 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Patch is a field of a JSON Merge Patch (RFC 7396) document. It tells an
+// absent member apart from an explicit null, so a PATCH only touches the
+// fields the client sent and null clears a column.
+type Patch[T any] struct {
+	Set   bool // the member is present in the document
+	Null  bool // the member is present and null
+	Value T
+}
+
+// UnmarshalJSON is only called for present members, including null ones.
+func (p *Patch[T]) UnmarshalJSON(data []byte) error {
+	p.Set = true
+
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		p.Null = true
+		return nil
+	}
+
+	return json.Unmarshal(data, &p.Value)
+}
+
+func (p Patch[T]) MarshalJSON() ([]byte, error) {
+	if !p.Set || p.Null {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(p.Value)
+}
+
+// IsNull reports whether the member was sent as an explicit null.
+func (p Patch[T]) IsNull() bool {
+	return p.Set && p.Null
+}
+
+// HasValue reports whether the member was sent with a non-null value.
+func (p Patch[T]) HasValue() bool {
+	return p.Set && !p.Null
+}
+
+// ValidationValue is what the validator checks: a pointer to the value, or
+// nil for absent and null members so "omitempty" skips them.
+func (p Patch[T]) ValidationValue() any {
+	if !p.HasValue() {
+		return nil
+	}
+
+	v := p.Value
+	return &v
+}
+
+// SQLValue is the value written to the column, nil for an explicit null.
+func (p Patch[T]) SQLValue() any {
+	if p.Null {
+		return nil
+	}
+
+	return p.Value
+}
+
+// NotNull returns the validation errors of members sent as null although
+// their column is NOT NULL, keyed by JSON name.
+func NotNull(fields map[string]interface{ IsNull() bool }) map[string][]string {
+	errors := make(map[string][]string)
+
+	for name, field := range fields {
+		if field.IsNull() {
+			errors[name] = append(errors[name], fmt.Sprintf("%s tidak boleh null.", strings.ReplaceAll(name, "_", " ")))
+		}
+	}
+
+	return errors
+}
+
+// PatchSet collects the "column = ?" assignments of a partial UPDATE.
+type PatchSet struct {
+	columns []string
+	args    []any
+}
+
+// Add assigns a value to a column.
+func (s *PatchSet) Add(column string, value any) {
+	s.columns = append(s.columns, column+" = ?")
+	s.args = append(s.args, value)
+}
+
+// AddPatch assigns the column only when the member is present in the patch.
+func AddPatch[T any](s *PatchSet, column string, p Patch[T]) {
+	if p.Set {
+		s.Add(column, p.SQLValue())
+	}
+}
+
+func (s *PatchSet) Empty() bool {
+	return len(s.columns) == 0
+}
+
+// Clause returns the assignments joined for a SET clause, with "?" placeholders.
+func (s *PatchSet) Clause() string {
+	return strings.Join(s.columns, ", ")
+}
+
+func (s *PatchSet) Args() []any {
+	return s.args
+}
@@ -11,13 +11,38 @@ import (
 // Point represents an x,y coordinate in EPSG:4326 for PostGIS.
 type Point [2]float64
 
+// NewPoint builds a Point from a latitude and longitude pair.
+func NewPoint(lat, lng float64) Point {
+	return Point{lng, lat}
+}
+
+// Lat returns the y coordinate of the point.
+func (p Point) Lat() float64 {
+	return p[1]
+}
+
+// Lng returns the x coordinate of the point.
+func (p Point) Lng() float64 {
+	return p[0]
+}
+
 func (p *Point) String() string {
 	return fmt.Sprintf("SRID=4326;POINT(%v %v)", p[0], p[1])
 }
 
 // Scan implements the sql.Scanner interface.
 func (p *Point) Scan(val interface{}) error {
-	b, err := hex.DecodeString(string(val.([]uint8)))
+	var raw string
+	switch v := val.(type) {
+	case []uint8:
+		raw = string(v)
+	case string:
+		raw = v
+	default:
+		return fmt.Errorf("unsupported point type %T", val)
+	}
+
+	b, err := hex.DecodeString(raw)
 	if err != nil {
 		return err
 	}
@@ -1,12 +1,15 @@
 package validator
 
 import (
+	"codebase-app/pkg/types"
+	"encoding/base64"
 	"reflect"
 	"strings"
 
 	// "github.com/go-playground/locales/en"
 	// ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
+	"github.com/shopspring/decimal"
 	// en_translations "github.com/go-playground/validator/v10/translations/en"
 	"github.com/rs/zerolog/log"
 )
@@ -62,6 +65,23 @@ func NewValidator() *Validator {
 	if err := v.RegisterValidation("unique_in_slice", isUniqueInSlice); err != nil {
 		log.Fatal().Err(err).Msg("Error while registering unique validator")
 	}
+	if err := v.RegisterValidation("slug", isSlug); err != nil {
+		log.Fatal().Err(err).Msg("Error while registering slug validator")
+	}
+	if err := v.RegisterValidation("base64_file", isBase64File); err != nil {
+		log.Fatal().Err(err).Msg("Error while registering base64_file validator")
+	}
+
+	// decimals are validated as numbers, so "required,gt=0" works on prices
+	v.RegisterCustomTypeFunc(decimalValue, decimal.Decimal{})
+
+	// merge patch fields are only validated when present with a value
+	v.RegisterCustomTypeFunc(patchValue,
+		types.Patch[string]{},
+		types.Patch[int64]{},
+		types.Patch[float64]{},
+		types.Patch[decimal.Decimal]{},
+	)
 
 	validatorCustom.validator = v
 	// validatorCustom.trans = trans
@@ -131,3 +151,54 @@ func isUniqueInSlice(fl validator.FieldLevel) bool {
 	}
 	return true
 }
+
+// slug validator, ex: "toko-baju-murah"
+func isSlug(fl validator.FieldLevel) bool {
+	slug := fl.Field().String()
+	if slug == "" || strings.HasPrefix(slug, "-") || strings.HasSuffix(slug, "-") || strings.Contains(slug, "--") {
+		return false
+	}
+
+	for _, char := range slug {
+		if !((char >= 'a' && char <= 'z') || (char >= '0' && char <= '9') || char == '-') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// base64 file validator, accepts both raw base64 and data URI ("data:application/pdf;base64,...")
+func isBase64File(fl validator.FieldLevel) bool {
+	content := fl.Field().String()
+	if strings.HasPrefix(content, "data:") {
+		idx := strings.Index(content, ";base64,")
+		if idx == -1 {
+			return false
+		}
+		content = content[idx+len(";base64,"):]
+	}
+
+	if content == "" {
+		return false
+	}
+
+	_, err := base64.StdEncoding.DecodeString(content)
+	return err == nil
+}
+
+func decimalValue(field reflect.Value) interface{} {
+	if d, ok := field.Interface().(decimal.Decimal); ok {
+		return d.InexactFloat64()
+	}
+
+	return nil
+}
+
+func patchValue(field reflect.Value) interface{} {
+	if p, ok := field.Interface().(interface{ ValidationValue() any }); ok {
+		return p.ValidationValue()
+	}
+
+	return nil
+}